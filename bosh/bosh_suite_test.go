@@ -1,6 +1,9 @@
 package bosh_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"testing"
 
 	"github.com/pivotal-cf-experimental/bosh-test/bosh"
@@ -16,4 +19,29 @@ func TestBosh(t *testing.T) {
 
 var _ = AfterEach(func() {
 	bosh.ResetBodyReader()
+	bosh.ResetUploadProgressInterval()
 })
+
+func buildTarball(files map[string]string) []byte {
+	buffer := bytes.NewBuffer([]byte{})
+	gzipWriter := gzip.NewWriter(buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for name, contents := range files {
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(contents)),
+			Typeflag: tar.TypeReg,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = tarWriter.Write([]byte(contents))
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	Expect(tarWriter.Close()).To(Succeed())
+	Expect(gzipWriter.Close()).To(Succeed())
+
+	return buffer.Bytes()
+}
@@ -1,14 +1,19 @@
 package bosh
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
 )
 
 func (c Client) Cleanup() (int, error) {
+	return c.CleanupContext(context.Background())
+}
+
+func (c Client) CleanupContext(ctx context.Context) (int, error) {
 	body := strings.NewReader(`{"config": {"remove_all": true}}`)
-	request, err := http.NewRequest("POST", fmt.Sprintf("%s/cleanup", c.config.URL), body)
+	request, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/cleanup", c.config.URL), body)
 	if err != nil {
 		return 0, err
 	}
@@ -21,8 +26,14 @@ func (c Client) Cleanup() (int, error) {
 	}
 
 	if response.StatusCode != http.StatusFound {
-		return 0, fmt.Errorf("unexpected response %s", response.Status)
+		body, err := bodyReader(response.Body)
+		if err != nil {
+			return 0, err
+		}
+		defer response.Body.Close()
+
+		return 0, newDirectorError(response, body)
 	}
 
-	return c.checkTaskStatus(response.Header.Get("Location"))
+	return c.checkTaskStatus(ctx, response.Header.Get("Location"))
 }
@@ -2,46 +2,76 @@ package bosh
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"net"
 	"net/http"
 	"net/url"
+	"path"
+	"strconv"
 	"time"
-
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/clientcredentials"
 )
 
 var (
-	client     = http.DefaultClient
-	transport  = http.DefaultTransport
-	bodyReader = ioutil.ReadAll
+	bodyReader        = ioutil.ReadAll
+	taskCancelTimeout = 30 * time.Second
 )
 
 type Config struct {
 	URL                 string
 	Host                string
 	DirectorCACert      string
+	ClientCert          string
+	ClientKey           string
 	Username            string
 	Password            string
 	TaskPollingInterval time.Duration
 	AllowInsecureSSL    bool
 	Transport           http.RoundTripper
 	UAA                 bool
+	UAAURL              string
+	UAAClientID         string
+	UAAClientSecret     string
+	UAAPasswordGrant    bool
+	UAARefreshToken     string
+	TaskArtifactsDir    string
 }
 
 type Client struct {
-	config Config
+	config     Config
+	httpClient *http.Client
+	uaa        *uaaAuth
 }
 
 type Task struct {
-	Id     int
-	State  string
-	Result string
+	Id          int
+	State       string
+	Description string
+	Timestamp   int64
+	StartedAt   int64 `json:"started_at"`
+	Result      string
+	User        string
+	Deployment  string
+	ContextID   string `json:"context_id"`
+}
+
+type TaskContextError struct {
+	TaskID    int
+	Err       error
+	CancelErr error
+}
+
+func (e TaskContextError) Error() string {
+	if e.CancelErr != nil {
+		return fmt.Sprintf("stopped waiting on bosh task %d: %s (failed to cancel task: %s)", e.TaskID, e.Err, e.CancelErr)
+	}
+
+	return fmt.Sprintf("stopped waiting on bosh task %d: %s", e.TaskID, e.Err)
+}
+
+func (e TaskContextError) Unwrap() error {
+	return e.Err
 }
 
 func NewClient(config Config) Client {
@@ -49,20 +79,16 @@ func NewClient(config Config) Client {
 		config.TaskPollingInterval = 5 * time.Second
 	}
 
-	config.Transport = http.DefaultTransport
-	if config.AllowInsecureSSL {
-		config.Transport = &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		}
-	}
-
-	client = &http.Client{
-		Transport: config.Transport,
+	if config.Transport == nil {
+		config.Transport = newTransport(config)
 	}
 
-	transport = config.Transport
 	return Client{
 		config: config,
+		httpClient: &http.Client{
+			Transport: config.Transport,
+		},
+		uaa: &uaaAuth{},
 	}
 }
 
@@ -82,24 +108,33 @@ func (c Client) rewriteURL(uri string) (string, error) {
 	return c.config.URL + parsedURL.String(), nil
 }
 
-func (c Client) checkTask(location string) (Task, error) {
+func (c Client) checkTask(ctx context.Context, location string) (Task, error) {
 	location, err := c.rewriteURL(location)
 	if err != nil {
 		return Task{}, err
 	}
 
 	var task Task
-	request, err := http.NewRequest("GET", location, nil)
+	request, err := http.NewRequestWithContext(ctx, "GET", location, nil)
 	if err != nil {
 		return task, err
 	}
 	response, err := c.makeRequest(request)
+	if err != nil {
+		return task, err
+	}
 
+	body, err := bodyReader(response.Body)
 	if err != nil {
 		return task, err
 	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusBadRequest {
+		return task, newDirectorError(response, body)
+	}
 
-	err = json.NewDecoder(response.Body).Decode(&task)
+	err = json.Unmarshal(body, &task)
 	if err != nil {
 		return task, err
 	}
@@ -107,61 +142,126 @@ func (c Client) checkTask(location string) (Task, error) {
 	return task, nil
 }
 
-func (c Client) checkTaskStatus(location string) (int, error) {
+func (c Client) checkTaskStatus(ctx context.Context, location string) (int, error) {
+	return c.pollTask(ctx, location, true)
+}
+
+// pollTask only cancels the director task when the context ends if
+// cancelOnDone is set, which is reserved for tasks this client started.
+func (c Client) pollTask(ctx context.Context, location string, cancelOnDone bool) (int, error) {
+	stopPolling := func() (int, error) {
+		if !cancelOnDone {
+			return 0, ctx.Err()
+		}
+
+		return c.abandonTask(location, ctx.Err())
+	}
+
 	for {
-		task, err := c.checkTask(location)
+		task, err := c.checkTask(ctx, location)
 		if err != nil {
+			if ctx.Err() != nil {
+				return stopPolling()
+			}
 			return 0, err
 		}
 
-		switch task.State {
-		case "done":
-			return task.Id, nil
-		case "error":
-			taskOutputs, err := c.GetTaskOutput(task.Id)
-			if err != nil {
-				return task.Id, fmt.Errorf("failed to get full bosh task event log, bosh task failed with an error status %q", task.Result)
-			}
-			return task.Id, taskOutputs[len(taskOutputs)-1].Error
-		case "errored":
-			taskOutputs, err := c.GetTaskOutput(task.Id)
-			if err != nil {
-				return task.Id, fmt.Errorf("failed to get full bosh task event log, bosh task failed with an errored status %q", task.Result)
-			}
-			return task.Id, taskOutputs[len(taskOutputs)-1].Error
-		case "cancelled":
-			return task.Id, errors.New("bosh task was cancelled")
-		default:
-			time.Sleep(c.config.TaskPollingInterval)
+		finished, err := c.finishedTask(ctx, task)
+		if finished {
+			return task.Id, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return stopPolling()
+		case <-time.After(c.config.TaskPollingInterval):
 		}
 	}
 }
 
-func (c Client) makeRequest(request *http.Request) (*http.Response, error) {
-	if c.config.UAA {
-		urlParts, err := url.Parse(c.config.URL)
+func (c Client) finishedTask(ctx context.Context, task Task) (bool, error) {
+	switch task.State {
+	case "done":
+		return true, nil
+	case "error", "errored":
+		c.saveTaskArtifacts(ctx, task.Id)
+		taskOutputs, err := c.GetTaskOutputContext(ctx, task.Id)
 		if err != nil {
-			return &http.Response{}, err
+			return true, fmt.Errorf("failed to get full bosh task event log, bosh task failed with an %s status %q", task.State, task.Result)
 		}
+		return true, newTaskFailedError(task, taskOutputs)
+	case "cancelled":
+		return true, errors.New("bosh task was cancelled")
+	default:
+		return false, nil
+	}
+}
 
-		boshHost, _, err := net.SplitHostPort(urlParts.Host)
-		if err != nil {
-			return &http.Response{}, err
-		}
+func (c Client) abandonTask(location string, ctxErr error) (int, error) {
+	taskID, err := taskIDFromLocation(location)
+	if err != nil {
+		return 0, TaskContextError{Err: ctxErr, CancelErr: err}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), taskCancelTimeout)
+	defer cancel()
+
+	return taskID, TaskContextError{
+		TaskID:    taskID,
+		Err:       ctxErr,
+		CancelErr: c.cancelTask(ctx, taskID),
+	}
+}
+
+func (c Client) cancelTask(ctx context.Context, taskID int) error {
+	request, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/tasks/%d", c.config.URL, taskID), nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.makeRequest(request)
+	if err != nil {
+		return err
+	}
+
+	body, err := bodyReader(response.Body)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
 
-		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{
-			Transport: c.config.Transport,
-		})
-		conf := &clientcredentials.Config{
-			ClientID:     c.config.Username,
-			ClientSecret: c.config.Password,
-			TokenURL:     fmt.Sprintf("https://%s:8443/oauth/token", boshHost),
+	if response.StatusCode != http.StatusNoContent {
+		return newDirectorError(response, body)
+	}
+
+	return nil
+}
+
+func taskIDFromLocation(location string) (int, error) {
+	parsedURL, err := url.Parse(location)
+	if err != nil {
+		return 0, err
+	}
+
+	taskID, err := strconv.Atoi(path.Base(parsedURL.Path))
+	if err != nil {
+		return 0, fmt.Errorf("could not determine task id from location %q", location)
+	}
+
+	return taskID, nil
+}
+
+func (c Client) makeRequest(request *http.Request) (*http.Response, error) {
+	if c.config.UAA {
+		token, err := c.uaaToken(request.Context())
+		if err != nil {
+			return nil, err
 		}
 
-		httpClient := conf.Client(ctx)
-		return httpClient.Do(request)
+		token.SetAuthHeader(request)
 	} else {
 		request.SetBasicAuth(c.config.Username, c.config.Password)
-		return transport.RoundTrip(request)
 	}
+
+	return c.config.Transport.RoundTrip(request)
 }
@@ -0,0 +1,87 @@
+package bosh_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type recordingTransport struct {
+	requests []*http.Request
+}
+
+func (t *recordingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, request)
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+var _ = Describe("Client", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"uuid": "some-uuid", "cpi": "some-cpi"}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("keeps the TLS settings of each client separate", func() {
+		insecureClient := bosh.NewClient(bosh.Config{
+			URL:              server.URL,
+			AllowInsecureSSL: true,
+		})
+
+		secureClient := bosh.NewClient(bosh.Config{
+			URL: server.URL,
+		})
+
+		var wg sync.WaitGroup
+		insecureErrs := make([]error, 10)
+		secureErrs := make([]error, 10)
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, insecureErrs[i] = insecureClient.Info()
+			}(i)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, secureErrs[i] = secureClient.Info()
+			}(i)
+		}
+		wg.Wait()
+
+		for i := 0; i < 10; i++ {
+			Expect(insecureErrs[i]).NotTo(HaveOccurred())
+			Expect(secureErrs[i]).To(MatchError(ContainSubstring("certificate")))
+		}
+	})
+
+	It("uses the transport given in the config", func() {
+		plainServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`[]`))
+		}))
+		defer plainServer.Close()
+
+		transport := &recordingTransport{}
+		client := bosh.NewClient(bosh.Config{
+			URL:       plainServer.URL,
+			Transport: transport,
+		})
+
+		_, err := client.Locks()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transport.requests).To(HaveLen(1))
+		Expect(transport.requests[0].URL.Path).To(Equal("/locks"))
+	})
+})
@@ -0,0 +1,268 @@
+package bosh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const defaultConfigName = "default"
+
+type DirectorConfig struct {
+	ID        string
+	Type      string
+	Name      string
+	Content   string
+	CreatedAt string `json:"created_at"`
+	Team      string
+	Current   bool
+}
+
+type ConfigFilter struct {
+	Type            string
+	Name            string
+	IncludeOutdated bool
+}
+
+type DiffLine struct {
+	Text   string
+	Change string
+}
+
+func (l DiffLine) Added() bool {
+	return l.Change == "added"
+}
+
+func (l DiffLine) Removed() bool {
+	return l.Change == "removed"
+}
+
+type configRequest struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+func (c Client) Configs(filter ConfigFilter) ([]DirectorConfig, error) {
+	return c.ConfigsContext(context.Background(), filter)
+}
+
+func (c Client) ConfigsContext(ctx context.Context, filter ConfigFilter) ([]DirectorConfig, error) {
+	query := url.Values{}
+	query.Set("latest", fmt.Sprintf("%t", !filter.IncludeOutdated))
+	if filter.Type != "" {
+		query.Set("type", filter.Type)
+	}
+
+	if filter.Name != "" {
+		query.Set("name", filter.Name)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/configs?%s", c.config.URL, query.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.makeRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := bodyReader(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, newDirectorError(response, body)
+	}
+
+	var configs []DirectorConfig
+	err = json.Unmarshal(body, &configs)
+	if err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
+
+func (c Client) LatestConfig(configType, name string) (DirectorConfig, error) {
+	return c.LatestConfigContext(context.Background(), configType, name)
+}
+
+func (c Client) LatestConfigContext(ctx context.Context, configType, name string) (DirectorConfig, error) {
+	configs, err := c.ConfigsContext(ctx, ConfigFilter{
+		Type: configType,
+		Name: name,
+	})
+	if err != nil {
+		return DirectorConfig{}, err
+	}
+
+	for _, config := range configs {
+		if config.Type == configType && config.Name == name {
+			return config, nil
+		}
+	}
+
+	return DirectorConfig{}, fmt.Errorf("%s config %q could not be found: %w", configType, name, ErrConfigNotFound)
+}
+
+func (c Client) CloudConfig() (DirectorConfig, error) {
+	return c.CloudConfigContext(context.Background())
+}
+
+func (c Client) CloudConfigContext(ctx context.Context) (DirectorConfig, error) {
+	return c.LatestConfigContext(ctx, "cloud", defaultConfigName)
+}
+
+func (c Client) RuntimeConfig(name string) (DirectorConfig, error) {
+	return c.RuntimeConfigContext(context.Background(), name)
+}
+
+func (c Client) RuntimeConfigContext(ctx context.Context, name string) (DirectorConfig, error) {
+	if name == "" {
+		name = defaultConfigName
+	}
+
+	return c.LatestConfigContext(ctx, "runtime", name)
+}
+
+func (c Client) UpdateConfig(configType, name string, content []byte) (DirectorConfig, error) {
+	return c.UpdateConfigContext(context.Background(), configType, name, content)
+}
+
+func (c Client) UpdateConfigContext(ctx context.Context, configType, name string, content []byte) (DirectorConfig, error) {
+	response, body, err := c.postConfig(ctx, "/configs", configType, name, content)
+	if err != nil {
+		return DirectorConfig{}, err
+	}
+
+	if response.StatusCode != http.StatusCreated && response.StatusCode != http.StatusOK {
+		return DirectorConfig{}, newDirectorError(response, body)
+	}
+
+	var config DirectorConfig
+	err = json.Unmarshal(body, &config)
+	if err != nil {
+		return DirectorConfig{}, err
+	}
+
+	return config, nil
+}
+
+func (c Client) DiffConfig(configType, name string, content []byte) ([]DiffLine, error) {
+	return c.DiffConfigContext(context.Background(), configType, name, content)
+}
+
+func (c Client) DiffConfigContext(ctx context.Context, configType, name string, content []byte) ([]DiffLine, error) {
+	response, body, err := c.postConfig(ctx, "/configs/diff", configType, name, content)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, newDirectorError(response, body)
+	}
+
+	var result struct {
+		Diff [][]interface{}
+	}
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseDiffLines(result.Diff), nil
+}
+
+func (c Client) DeleteConfig(configType, name string) error {
+	return c.DeleteConfigContext(context.Background(), configType, name)
+}
+
+func (c Client) DeleteConfigContext(ctx context.Context, configType, name string) error {
+	query := url.Values{}
+	query.Set("type", configType)
+	query.Set("name", name)
+
+	request, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/configs?%s", c.config.URL, query.Encode()), nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.makeRequest(request)
+	if err != nil {
+		return err
+	}
+
+	body, err := bodyReader(response.Body)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		directorErr := newDirectorError(response, body)
+		directorErr.kind = ErrConfigNotFound
+		return directorErr
+	}
+
+	if response.StatusCode != http.StatusNoContent {
+		return newDirectorError(response, body)
+	}
+
+	return nil
+}
+
+func (c Client) postConfig(ctx context.Context, path, configType, name string, content []byte) (*http.Response, []byte, error) {
+	requestBody, err := json.Marshal(configRequest{
+		Type:    configType,
+		Name:    name,
+		Content: string(content),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s%s", c.config.URL, path), bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := c.makeRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := bodyReader(response.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+
+	return response, body, nil
+}
+
+func parseDiffLines(diff [][]interface{}) []DiffLine {
+	var lines []DiffLine
+	for _, entry := range diff {
+		var line DiffLine
+		if len(entry) > 0 {
+			line.Text, _ = entry[0].(string)
+		}
+
+		if len(entry) > 1 {
+			line.Change, _ = entry[1].(string)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
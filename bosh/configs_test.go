@@ -0,0 +1,234 @@
+package bosh_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Configs", func() {
+	Describe("Configs", func() {
+		It("lists the configs matching the filter", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/configs"))
+				Expect(r.Method).To(Equal("GET"))
+				Expect(r.URL.Query()).To(Equal(url.Values{
+					"type":   {"runtime"},
+					"name":   {"default"},
+					"latest": {"false"},
+				}))
+
+				username, password, ok := r.BasicAuth()
+				Expect(ok).To(BeTrue())
+				Expect(username).To(Equal("some-username"))
+				Expect(password).To(Equal("some-password"))
+
+				w.Write([]byte(`[
+					{"id": "2", "type": "runtime", "name": "default", "content": "addons: []", "created_at": "2017-11-01 00:00:00 UTC", "team": null, "current": true},
+					{"id": "1", "type": "cloud", "name": "default", "content": "vm_types: []", "created_at": "2017-10-01 00:00:00 UTC", "team": "some-team", "current": true}
+				]`))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:      server.URL,
+				Username: "some-username",
+				Password: "some-password",
+			})
+
+			configs, err := client.Configs(bosh.ConfigFilter{
+				Type:            "runtime",
+				Name:            "default",
+				IncludeOutdated: true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(configs).To(Equal([]bosh.DirectorConfig{
+				{ID: "2", Type: "runtime", Name: "default", Content: "addons: []", CreatedAt: "2017-11-01 00:00:00 UTC", Current: true},
+				{ID: "1", Type: "cloud", Name: "default", Content: "vm_types: []", CreatedAt: "2017-10-01 00:00:00 UTC", Team: "some-team", Current: true},
+			}))
+		})
+	})
+
+	Describe("CloudConfig", func() {
+		It("returns the current default cloud config", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/configs"))
+				Expect(r.Method).To(Equal("GET"))
+				Expect(r.URL.Query()).To(Equal(url.Values{
+					"type":   {"cloud"},
+					"name":   {"default"},
+					"latest": {"true"},
+				}))
+
+				w.Write([]byte(`[{"id": "1", "type": "cloud", "name": "default", "content": "vm_types: []", "created_at": "2017-10-01 00:00:00 UTC", "team": null, "current": true}]`))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			config, err := client.CloudConfig()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Content).To(Equal("vm_types: []"))
+		})
+	})
+
+	Describe("RuntimeConfig", func() {
+		It("returns the current runtime config", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/configs"))
+				Expect(r.URL.Query().Get("type")).To(Equal("runtime"))
+				Expect(r.URL.Query().Get("name")).To(Equal("default"))
+
+				w.Write([]byte(`[{"id": "2", "type": "runtime", "name": "default", "content": "addons: []", "created_at": "2017-11-01 00:00:00 UTC", "team": null, "current": true}]`))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			config, err := client.RuntimeConfig("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.ID).To(Equal("2"))
+			Expect(config.Content).To(Equal("addons: []"))
+		})
+
+		It("returns a config not found error when there is no such config", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Query().Get("name")).To(Equal("missing"))
+
+				w.Write([]byte(`[]`))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			_, err := client.RuntimeConfig("missing")
+			Expect(err).To(MatchError(`runtime config "missing" could not be found: config not found`))
+			Expect(errors.Is(err, bosh.ErrConfigNotFound)).To(BeTrue())
+		})
+	})
+
+	Describe("UpdateConfig", func() {
+		It("creates a new config version", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/configs"))
+				Expect(r.Method).To(Equal("POST"))
+				Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+
+				username, password, ok := r.BasicAuth()
+				Expect(ok).To(BeTrue())
+				Expect(username).To(Equal("some-username"))
+				Expect(password).To(Equal("some-password"))
+
+				body, err := ioutil.ReadAll(r.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(body).To(MatchJSON(`{"type": "runtime", "name": "some-addon", "content": "addons: [{name: some-addon}]"}`))
+
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"id": "3", "type": "runtime", "name": "some-addon", "content": "addons: [{name: some-addon}]", "created_at": "2017-12-01 00:00:00 UTC", "team": null, "current": true}`))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:      server.URL,
+				Username: "some-username",
+				Password: "some-password",
+			})
+
+			config, err := client.UpdateConfig("runtime", "some-addon", []byte("addons: [{name: some-addon}]"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.ID).To(Equal("3"))
+			Expect(config.Name).To(Equal("some-addon"))
+		})
+
+		It("errors on an unexpected status code with a body", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte("More Info"))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			_, err := client.UpdateConfig("runtime", "some-addon", []byte("%%%"))
+			Expect(err).To(MatchError("unexpected response 400 Bad Request:\nMore Info"))
+		})
+	})
+
+	Describe("DiffConfig", func() {
+		It("returns the lines the director would change", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/configs/diff"))
+				Expect(r.Method).To(Equal("POST"))
+				Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+
+				body, err := ioutil.ReadAll(r.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(body).To(MatchJSON(`{"type": "runtime", "name": "default", "content": "addons: [{name: some-addon}]"}`))
+
+				w.Write([]byte(`{"diff": [["addons:", null], ["- name: some-addon", "added"], ["- name: some-old-addon", "removed"]]}`))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			diff, err := client.DiffConfig("runtime", "default", []byte("addons: [{name: some-addon}]"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(Equal([]bosh.DiffLine{
+				{Text: "addons:"},
+				{Text: "- name: some-addon", Change: "added"},
+				{Text: "- name: some-old-addon", Change: "removed"},
+			}))
+			Expect(diff[1].Added()).To(BeTrue())
+			Expect(diff[2].Removed()).To(BeTrue())
+		})
+	})
+
+	Describe("DeleteConfig", func() {
+		It("deletes the config", func() {
+			var deleted bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/configs"))
+				Expect(r.Method).To(Equal("DELETE"))
+				Expect(r.URL.Query()).To(Equal(url.Values{
+					"type": {"runtime"},
+					"name": {"some-addon"},
+				}))
+
+				deleted = true
+				w.WriteHeader(http.StatusNoContent)
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			err := client.DeleteConfig("runtime", "some-addon")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeTrue())
+		})
+
+		It("returns a config not found error when there is no such config", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"code": 440012, "description": "No configs to delete"}`))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			err := client.DeleteConfig("runtime", "missing")
+			Expect(errors.Is(err, bosh.ErrConfigNotFound)).To(BeTrue())
+		})
+	})
+})
@@ -1,17 +1,22 @@
 package bosh
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 )
 
 func (c Client) DeleteDeployment(name string) error {
+	return c.DeleteDeploymentContext(context.Background(), name)
+}
+
+func (c Client) DeleteDeploymentContext(ctx context.Context, name string) error {
 	if name == "" {
 		return errors.New("a valid deployment name is required")
 	}
 
-	request, err := http.NewRequest("DELETE", fmt.Sprintf("%s/deployments/%s?force=true", c.config.URL, name), nil)
+	request, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/deployments/%s?force=true", c.config.URL, name), nil)
 	if err != nil {
 		return err
 	}
@@ -29,9 +34,9 @@ func (c Client) DeleteDeployment(name string) error {
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusFound {
-		return fmt.Errorf("unexpected response %d %s:\n%s", response.StatusCode, http.StatusText(response.StatusCode), body)
+		return newDirectorError(response, body)
 	}
 
-	_, err = c.checkTaskStatus(response.Header.Get("Location"))
+	_, err = c.checkTaskStatus(ctx, response.Header.Get("Location"))
 	return err
 }
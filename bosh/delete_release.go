@@ -1,16 +1,21 @@
 package bosh
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
 )
 
 func (c Client) DeleteRelease(name, version string) error {
+	return c.DeleteReleaseContext(context.Background(), name, version)
+}
+
+func (c Client) DeleteReleaseContext(ctx context.Context, name, version string) error {
 	query := url.Values{}
 	query.Add("version", version)
 
-	request, err := http.NewRequest("DELETE", fmt.Sprintf("%s/releases/%s?%s", c.config.URL, name, query.Encode()), nil)
+	request, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/releases/%s?%s", c.config.URL, name, query.Encode()), nil)
 	if err != nil {
 		return err
 	}
@@ -28,10 +33,10 @@ func (c Client) DeleteRelease(name, version string) error {
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusFound {
-		return fmt.Errorf("unexpected response %d %s:\n%s", response.StatusCode, http.StatusText(response.StatusCode), body)
+		return newDirectorError(response, body)
 	}
 
-	_, err = c.checkTaskStatus(response.Header.Get("Location"))
+	_, err = c.checkTaskStatus(ctx, response.Header.Get("Location"))
 	if err != nil {
 		return err
 	}
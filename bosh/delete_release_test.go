@@ -125,7 +125,7 @@ var _ = Context("DeleteRelease", func() {
 				})
 
 				err := client.DeleteRelease("otherthing", "42")
-				Expect(err).To(MatchError("task error: 4999 has occurred: some other random error"))
+				Expect(err).To(MatchError("bosh task 1 failed with an error status \"Stemcell something/42 is still in use\":\n  - task error: 4999 has occurred: some other random error"))
 			})
 		})
 	})
@@ -1,6 +1,8 @@
 package bosh
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -9,10 +11,6 @@ type InUse struct {
 	Use bool
 }
 
-type errorStatus interface {
-	ErrorCode() int
-}
-
 func (i InUse) Error() string {
 	return "stemcell is in use"
 }
@@ -21,8 +19,16 @@ func (i InUse) InUse() bool {
 	return i.Use
 }
 
+func (i InUse) Is(target error) bool {
+	return i.Use && target == ErrStemcellInUse
+}
+
 func (c Client) DeleteStemcell(name, version string) error {
-	request, err := http.NewRequest("DELETE", fmt.Sprintf("%s/stemcells/%s/%s", c.config.URL, name, version), nil)
+	return c.DeleteStemcellContext(context.Background(), name, version)
+}
+
+func (c Client) DeleteStemcellContext(ctx context.Context, name, version string) error {
+	request, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/stemcells/%s/%s", c.config.URL, name, version), nil)
 	if err != nil {
 		return err
 	}
@@ -40,15 +46,13 @@ func (c Client) DeleteStemcell(name, version string) error {
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusFound {
-		return fmt.Errorf("unexpected response %d %s:\n%s", response.StatusCode, http.StatusText(response.StatusCode), body)
+		return newDirectorError(response, body)
 	}
 
-	_, err = c.checkTaskStatus(response.Header.Get("Location"))
+	_, err = c.checkTaskStatus(ctx, response.Header.Get("Location"))
 	if err != nil {
-		if errStatus, ok := err.(errorStatus); ok {
-			if errStatus.ErrorCode() == 50004 {
-				return InUse{true}
-			}
+		if errors.Is(err, ErrStemcellInUse) {
+			return InUse{true}
 		}
 
 		return err
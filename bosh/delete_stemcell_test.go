@@ -149,7 +149,7 @@ var _ = Context("DeleteStemcell", func() {
 				})
 
 				err := client.DeleteStemcell("something", "42")
-				Expect(err).To(MatchError("task error: 50003 has occurred: some other random error"))
+				Expect(err).To(MatchError("bosh task 1 failed with an error status \"Stemcell something/42 is still in use\":\n  - task error: 50003 has occurred: some other random error"))
 			})
 		})
 	})
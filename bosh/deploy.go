@@ -2,22 +2,108 @@ package bosh
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
+type DeployOptions struct {
+	Recreate                bool
+	RecreatePersistentDisks bool
+	Fix                     bool
+	SkipDrainAll            bool
+	SkipDrain               []string
+	Canaries                string
+	MaxInFlight             string
+	DryRun                  bool
+	ContextID               string
+	Context                 map[string]interface{}
+	Events                  func(TaskOutput)
+}
+
+func (o DeployOptions) query() (url.Values, error) {
+	query := url.Values{}
+	if o.Recreate {
+		query.Set("recreate", "true")
+	}
+
+	if o.RecreatePersistentDisks {
+		query.Set("recreate_persistent_disks", "true")
+	}
+
+	if o.Fix {
+		query.Set("fix", "true")
+	}
+
+	if o.SkipDrainAll {
+		query.Set("skip_drain", "*")
+	} else if len(o.SkipDrain) > 0 {
+		query.Set("skip_drain", strings.Join(o.SkipDrain, ","))
+	}
+
+	if o.Canaries != "" {
+		query.Set("canaries", o.Canaries)
+	}
+
+	if o.MaxInFlight != "" {
+		query.Set("max_in_flight", o.MaxInFlight)
+	}
+
+	if o.DryRun {
+		query.Set("dry_run", "true")
+	}
+
+	if len(o.Context) > 0 {
+		deployContext, err := json.Marshal(o.Context)
+		if err != nil {
+			return nil, err
+		}
+		query.Set("context", string(deployContext))
+	}
+
+	return query, nil
+}
+
 func (c Client) Deploy(manifest []byte) (int, error) {
+	return c.DeployContext(context.Background(), manifest)
+}
+
+func (c Client) DeployContext(ctx context.Context, manifest []byte) (int, error) {
+	return c.DeployWithOptionsContext(ctx, manifest, DeployOptions{})
+}
+
+func (c Client) DeployWithOptions(manifest []byte, options DeployOptions) (int, error) {
+	return c.DeployWithOptionsContext(context.Background(), manifest, options)
+}
+
+func (c Client) DeployWithOptionsContext(ctx context.Context, manifest []byte, options DeployOptions) (int, error) {
 	if len(manifest) == 0 {
 		return 0, errors.New("a valid manifest is required to deploy")
 	}
 
-	request, err := http.NewRequest("POST", fmt.Sprintf("%s/deployments", c.config.URL), bytes.NewBuffer(manifest))
+	query, err := options.query()
+	if err != nil {
+		return 0, err
+	}
+
+	location := fmt.Sprintf("%s/deployments", c.config.URL)
+	if len(query) > 0 {
+		location = fmt.Sprintf("%s?%s", location, query.Encode())
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "POST", location, bytes.NewBuffer(manifest))
 	if err != nil {
 		return 0, err
 	}
 
 	request.Header.Set("Content-Type", "text/yaml")
+	if options.ContextID != "" {
+		request.Header.Set("X-Bosh-Context-Id", options.ContextID)
+	}
 
 	response, err := c.makeRequest(request)
 	if err != nil {
@@ -31,8 +117,17 @@ func (c Client) Deploy(manifest []byte) (int, error) {
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusFound {
-		return 0, fmt.Errorf("unexpected response %d %s:\n%s", response.StatusCode, http.StatusText(response.StatusCode), body)
+		return 0, newDirectorError(response, body)
+	}
+
+	if options.Events != nil {
+		taskId, err := taskIDFromLocation(response.Header.Get("Location"))
+		if err != nil {
+			return 0, err
+		}
+
+		return taskId, c.watchTask(ctx, taskId, options.Events, true)
 	}
 
-	return c.checkTaskStatus(response.Header.Get("Location"))
+	return c.checkTaskStatus(ctx, response.Header.Get("Location"))
 }
@@ -1,12 +1,14 @@
 package bosh_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"time"
 
 	"github.com/pivotal-cf-experimental/bosh-test/bosh"
@@ -70,6 +72,171 @@ var _ = Describe("Deploy", func() {
 		Expect(taskId).To(Equal(1))
 	})
 
+	Describe("DeployWithOptions", func() {
+		var (
+			query     url.Values
+			contextID string
+			server    *httptest.Server
+		)
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/deployments":
+					Expect(r.Method).To(Equal("POST"))
+					query = r.URL.Query()
+					contextID = r.Header.Get("X-Bosh-Context-Id")
+
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/1", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/1":
+					w.Write([]byte(`{"id": 1, "state": "done"}`))
+				default:
+					Fail("could not match any URL endpoints")
+				}
+			}))
+		})
+
+		It("passes the deploy options to the director", func() {
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			taskId, err := client.DeployWithOptions([]byte("some-yaml"), bosh.DeployOptions{
+				Recreate:                true,
+				RecreatePersistentDisks: true,
+				Fix:                     true,
+				SkipDrain:               []string{"api", "worker"},
+				Canaries:                "2",
+				MaxInFlight:             "25%",
+				DryRun:                  true,
+				ContextID:               "some-context-id",
+				Context:                 map[string]interface{}{"cloud_config_ids": []int{1}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(taskId).To(Equal(1))
+
+			Expect(query).To(Equal(url.Values{
+				"recreate":                  {"true"},
+				"recreate_persistent_disks": {"true"},
+				"fix":                       {"true"},
+				"skip_drain":                {"api,worker"},
+				"canaries":                  {"2"},
+				"max_in_flight":             {"25%"},
+				"dry_run":                   {"true"},
+				"context":                   {`{"cloud_config_ids":[1]}`},
+			}))
+			Expect(contextID).To(Equal("some-context-id"))
+		})
+
+		It("skips drain on every instance group", func() {
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			_, err := client.DeployWithOptions([]byte("some-yaml"), bosh.DeployOptions{
+				SkipDrainAll: true,
+				SkipDrain:    []string{"api"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(query).To(Equal(url.Values{
+				"skip_drain": {"*"},
+			}))
+		})
+
+		It("sends no parameters without options", func() {
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			_, err := client.DeployWithOptions([]byte("some-yaml"), bosh.DeployOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(query).To(BeEmpty())
+			Expect(contextID).To(BeEmpty())
+		})
+
+		It("streams task events to the handler while the deploy runs", func() {
+			var polls int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/deployments":
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/3", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/3":
+					polls++
+					if polls < 2 {
+						w.Write([]byte(`{"id": 3, "state": "processing"}`))
+						return
+					}
+					w.Write([]byte(`{"id": 3, "state": "done"}`))
+				case "/tasks/3/output":
+					w.Write([]byte(`{"time":1,"stage":"Updating instance","tags":[],"total":1,"task":"api/0","index":1,"state":"started","progress":0}` + "\n"))
+				default:
+					Fail("could not match any URL endpoints")
+				}
+			}))
+			defer server.Close()
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			var events []bosh.TaskOutput
+			taskId, err := client.DeployWithOptions([]byte("some-yaml"), bosh.DeployOptions{
+				Events: func(event bosh.TaskOutput) {
+					events = append(events, event)
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(taskId).To(Equal(3))
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Stage).To(Equal("Updating instance"))
+			Expect(events[0].Task).To(Equal("api/0"))
+		})
+
+		It("cancels the deploy it started when the context is done while streaming events", func() {
+			var cancelledTask bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/deployments":
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/7", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/7":
+					if r.Method == "DELETE" {
+						cancelledTask = true
+						w.WriteHeader(http.StatusNoContent)
+						return
+					}
+					w.Write([]byte(`{"id": 7, "state": "processing"}`))
+				case "/tasks/7/output":
+					w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				default:
+					Fail("could not match any URL endpoints")
+				}
+			}))
+			defer server.Close()
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Millisecond,
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			taskId, err := client.DeployWithOptionsContext(ctx, []byte("some-yaml"), bosh.DeployOptions{
+				Events: func(bosh.TaskOutput) {},
+			})
+			Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+			Expect(taskId).To(Equal(7))
+			Expect(cancelledTask).To(BeTrue())
+		})
+	})
+
 	Context("failure cases", func() {
 		It("should error on a non 302 redirect response with a body", func() {
 			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -151,7 +318,7 @@ var _ = Describe("Deploy", func() {
 			})
 
 			_, err := client.Deploy([]byte("some-yaml"))
-			Expect(err).To(MatchError("task error: 100 has occurred: some-better-error-message"))
+			Expect(err).To(MatchError("bosh task 1 failed with an error status \"some-error-message\":\n  - task error: 100 has occurred: some-better-error-message"))
 		})
 
 		It("return result error if events error fails", func() {
@@ -238,7 +405,7 @@ var _ = Describe("Deploy", func() {
 			})
 
 			_, err := client.Deploy([]byte("some-yaml"))
-			Expect(err).To(MatchError("task error: 100 has occurred: some-better-error-message"))
+			Expect(err).To(MatchError("bosh task 1 failed with an errored status \"some-error-message\":\n  - task error: 100 has occurred: some-better-error-message"))
 		})
 
 		It("should error on a cancelled task status", func() {
@@ -330,4 +497,85 @@ var _ = Describe("Deploy", func() {
 			Expect(err).To(MatchError(ContainSubstring("invalid character")))
 		})
 	})
+
+	Context("when the context is done before the task finishes", func() {
+		It("cancels the task and returns an error carrying the task id", func() {
+			var cancelledTask bool
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/deployments":
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/7", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/7":
+					if r.Method == "DELETE" {
+						username, password, ok := r.BasicAuth()
+						Expect(ok).To(BeTrue())
+						Expect(username).To(Equal("some-username"))
+						Expect(password).To(Equal("some-password"))
+
+						cancelledTask = true
+						w.WriteHeader(http.StatusNoContent)
+						return
+					}
+
+					w.Write([]byte(`{"id": 7, "state": "processing"}`))
+				default:
+					Fail("could not match any URL endpoints")
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				Username:            "some-username",
+				Password:            "some-password",
+				TaskPollingInterval: time.Millisecond,
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			taskId, err := client.DeployContext(ctx, []byte("some-yaml"))
+			Expect(err).To(MatchError("stopped waiting on bosh task 7: context deadline exceeded"))
+			Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+
+			var taskErr bosh.TaskContextError
+			Expect(errors.As(err, &taskErr)).To(BeTrue())
+			Expect(taskErr.TaskID).To(Equal(7))
+			Expect(taskId).To(Equal(7))
+			Expect(cancelledTask).To(BeTrue())
+		})
+
+		It("reports when the task could not be cancelled", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/deployments":
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/7", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/7":
+					if r.Method == "DELETE" {
+						w.WriteHeader(http.StatusBadRequest)
+						w.Write([]byte("More Info"))
+						return
+					}
+
+					w.Write([]byte(`{"id": 7, "state": "processing"}`))
+				default:
+					Fail("could not match any URL endpoints")
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Millisecond,
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			_, err := client.DeployContext(ctx, []byte("some-yaml"))
+			Expect(err).To(MatchError("stopped waiting on bosh task 7: context deadline exceeded (failed to cancel task: unexpected response 400 Bad Request:\nMore Info)"))
+			Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		})
+	})
 })
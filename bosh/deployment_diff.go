@@ -0,0 +1,115 @@
+package bosh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+var instanceGroupNamePattern = regexp.MustCompile(`^- name: (\S+)`)
+
+type DiffOptions struct {
+	Unredacted bool
+}
+
+type DeploymentDiff struct {
+	Lines   []DiffLine
+	Context map[string]interface{}
+}
+
+func (d DeploymentDiff) Changed() bool {
+	for _, line := range d.Lines {
+		if line.Added() || line.Removed() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d DeploymentDiff) ChangedInstanceGroups() []string {
+	var (
+		section       string
+		instanceGroup string
+		changed       []string
+		seen          = map[string]bool{}
+	)
+
+	for _, line := range d.Lines {
+		if line.Text != "" && !strings.HasPrefix(line.Text, " ") && !strings.HasPrefix(line.Text, "-") {
+			section = strings.TrimSuffix(line.Text, ":")
+			instanceGroup = ""
+		}
+
+		if section != "instance_groups" && section != "jobs" {
+			continue
+		}
+
+		if matches := instanceGroupNamePattern.FindStringSubmatch(line.Text); matches != nil {
+			instanceGroup = matches[1]
+		}
+
+		if instanceGroup != "" && (line.Added() || line.Removed()) && !seen[instanceGroup] {
+			seen[instanceGroup] = true
+			changed = append(changed, instanceGroup)
+		}
+	}
+
+	return changed
+}
+
+func (c Client) DiffDeployment(name string, manifest []byte, options DiffOptions) (DeploymentDiff, error) {
+	return c.DiffDeploymentContext(context.Background(), name, manifest, options)
+}
+
+func (c Client) DiffDeploymentContext(ctx context.Context, name string, manifest []byte, options DiffOptions) (DeploymentDiff, error) {
+	if len(manifest) == 0 {
+		return DeploymentDiff{}, errors.New("a valid manifest is required to diff")
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/deployments/%s/diff?redact=%t", c.config.URL, name, !options.Unredacted), bytes.NewBuffer(manifest))
+	if err != nil {
+		return DeploymentDiff{}, err
+	}
+
+	request.Header.Set("Content-Type", "text/yaml")
+
+	response, err := c.makeRequest(request)
+	if err != nil {
+		return DeploymentDiff{}, err
+	}
+
+	body, err := bodyReader(response.Body)
+	if err != nil {
+		return DeploymentDiff{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return DeploymentDiff{}, newDirectorError(response, body)
+	}
+
+	var result struct {
+		Context map[string]interface{}
+		Diff    [][]interface{}
+		Error   string
+	}
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return DeploymentDiff{}, err
+	}
+
+	if result.Error != "" {
+		return DeploymentDiff{}, fmt.Errorf("failed to diff deployment %s: %s", name, result.Error)
+	}
+
+	return DeploymentDiff{
+		Lines:   parseDiffLines(result.Diff),
+		Context: result.Context,
+	}, nil
+}
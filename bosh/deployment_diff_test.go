@@ -0,0 +1,129 @@
+package bosh_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DiffDeployment", func() {
+	var (
+		server   *httptest.Server
+		rawQuery string
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal("POST"))
+			Expect(r.URL.Path).To(Equal("/deployments/some-deployment/diff"))
+			Expect(r.Header.Get("Content-Type")).To(Equal("text/yaml"))
+
+			username, password, ok := r.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(username).To(Equal("some-username"))
+			Expect(password).To(Equal("some-password"))
+
+			body, err := ioutil.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(body)).To(Equal("some-yaml"))
+
+			rawQuery = r.URL.RawQuery
+
+			w.Write([]byte(`{
+				"context": {"cloud_config_ids": [1], "runtime_config_ids": [2, 3]},
+				"diff": [
+					["instance_groups:", null],
+					["- name: api", null],
+					["  instances: 2", "removed"],
+					["  instances: 3", "added"],
+					["- name: router", null],
+					["  instances: 1", null],
+					["- name: worker", "added"],
+					["  instances: 1", "added"],
+					["properties:", null],
+					["  some-secret: \"<redacted>\"", "added"]
+				]
+			}`))
+		}))
+	})
+
+	It("returns the diff reported by the director with redaction on", func() {
+		client := bosh.NewClient(bosh.Config{
+			URL:      server.URL,
+			Username: "some-username",
+			Password: "some-password",
+		})
+
+		diff, err := client.DiffDeployment("some-deployment", []byte("some-yaml"), bosh.DiffOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rawQuery).To(Equal("redact=true"))
+
+		Expect(diff.Context).To(Equal(map[string]interface{}{
+			"cloud_config_ids":   []interface{}{float64(1)},
+			"runtime_config_ids": []interface{}{float64(2), float64(3)},
+		}))
+		Expect(diff.Lines).To(HaveLen(10))
+		Expect(diff.Lines[2]).To(Equal(bosh.DiffLine{Text: "  instances: 2", Change: "removed"}))
+		Expect(diff.Changed()).To(BeTrue())
+		Expect(diff.ChangedInstanceGroups()).To(Equal([]string{"api", "worker"}))
+	})
+
+	It("turns off redaction when asked", func() {
+		client := bosh.NewClient(bosh.Config{
+			URL:      server.URL,
+			Username: "some-username",
+			Password: "some-password",
+		})
+
+		_, err := client.DiffDeployment("some-deployment", []byte("some-yaml"), bosh.DiffOptions{Unredacted: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rawQuery).To(Equal("redact=false"))
+	})
+
+	It("reports no changes for an empty diff", func() {
+		diff := bosh.DeploymentDiff{Lines: []bosh.DiffLine{{Text: "name: some-deployment"}}}
+		Expect(diff.Changed()).To(BeFalse())
+		Expect(diff.ChangedInstanceGroups()).To(BeEmpty())
+	})
+
+	Context("failure cases", func() {
+		It("errors when there is no manifest", func() {
+			client := bosh.NewClient(bosh.Config{})
+
+			_, err := client.DiffDeployment("some-deployment", nil, bosh.DiffOptions{})
+			Expect(err).To(MatchError("a valid manifest is required to diff"))
+		})
+
+		It("errors when the director could not diff the manifest", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"diff": [], "error": "Unable to diff manifest"}`))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			_, err := client.DiffDeployment("some-deployment", []byte("some-yaml"), bosh.DiffOptions{})
+			Expect(err).To(MatchError("failed to diff deployment some-deployment: Unable to diff manifest"))
+		})
+
+		It("errors on an unexpected status code with a body", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte("More Info"))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			_, err := client.DiffDeployment("some-deployment", []byte("some-yaml"), bosh.DiffOptions{})
+			Expect(err).To(MatchError("unexpected response 400 Bad Request:\nMore Info"))
+		})
+	})
+})
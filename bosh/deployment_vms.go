@@ -2,6 +2,7 @@ package bosh
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -16,7 +17,11 @@ type VM struct {
 }
 
 func (c Client) DeploymentVMs(name string) ([]VM, error) {
-	request, err := http.NewRequest("GET", fmt.Sprintf("%s/deployments/%s/vms?format=full", c.config.URL, name), nil)
+	return c.DeploymentVMsContext(context.Background(), name)
+}
+
+func (c Client) DeploymentVMsContext(ctx context.Context, name string) ([]VM, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/deployments/%s/vms?format=full", c.config.URL, name), nil)
 	if err != nil {
 		return []VM{}, err
 	}
@@ -33,12 +38,12 @@ func (c Client) DeploymentVMs(name string) ([]VM, error) {
 		}
 		defer response.Body.Close()
 
-		return []VM{}, fmt.Errorf("unexpected response %d %s:\n%s", response.StatusCode, http.StatusText(response.StatusCode), body)
+		return []VM{}, newDirectorError(response, body)
 	}
 
 	location := response.Header.Get("Location")
 
-	_, err = c.checkTaskStatus(location)
+	_, err = c.checkTaskStatus(ctx, location)
 	if err != nil {
 		return []VM{}, err
 	}
@@ -48,13 +53,12 @@ func (c Client) DeploymentVMs(name string) ([]VM, error) {
 		return []VM{}, err
 	}
 
-	request, err = http.NewRequest("GET", fmt.Sprintf("%s/output?type=result", location), nil)
+	request, err = http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/output?type=result", location), nil)
 	if err != nil {
 		return []VM{}, err
 	}
 
-	request.SetBasicAuth(c.config.Username, c.config.Password)
-	response, err = transport.RoundTrip(request)
+	response, err = c.makeRequest(request)
 	if err != nil {
 		return []VM{}, err
 	}
@@ -1,6 +1,7 @@
 package bosh
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,7 +15,11 @@ type Deployment struct {
 }
 
 func (c Client) Deployments() ([]Deployment, error) {
-	request, err := http.NewRequest("GET", fmt.Sprintf("%s/deployments", c.config.URL), nil)
+	return c.DeploymentsContext(context.Background())
+}
+
+func (c Client) DeploymentsContext(ctx context.Context) ([]Deployment, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/deployments", c.config.URL), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -31,7 +36,7 @@ func (c Client) Deployments() ([]Deployment, error) {
 		}
 		defer response.Body.Close()
 
-		return nil, fmt.Errorf("unexpected response %d %s:\n%s", response.StatusCode, http.StatusText(response.StatusCode), body)
+		return nil, newDirectorError(response, body)
 	}
 
 	var jsonDeployments []struct {
@@ -1,6 +1,7 @@
 package bosh
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,7 +12,11 @@ type deploymentManifest struct {
 }
 
 func (c Client) DownloadManifest(deploymentName string) ([]byte, error) {
-	request, err := http.NewRequest("GET", fmt.Sprintf("%s/deployments/%s", c.config.URL, deploymentName), nil)
+	return c.DownloadManifestContext(context.Background(), deploymentName)
+}
+
+func (c Client) DownloadManifestContext(ctx context.Context, deploymentName string) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/deployments/%s", c.config.URL, deploymentName), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -28,7 +33,7 @@ func (c Client) DownloadManifest(deploymentName string) ([]byte, error) {
 		}
 		defer response.Body.Close()
 
-		return nil, fmt.Errorf("unexpected response %d %s:\n%s", response.StatusCode, http.StatusText(response.StatusCode), body)
+		return nil, newDirectorError(response, body)
 	}
 
 	var result deploymentManifest
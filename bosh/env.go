@@ -0,0 +1,177 @@
+package bosh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const defaultDirectorPort = "25555"
+
+type cliConfig struct {
+	Environments []cliEnvironment `yaml:"environments"`
+}
+
+type cliEnvironment struct {
+	URL          string `yaml:"url"`
+	CACert       string `yaml:"ca_cert"`
+	Alias        string `yaml:"alias"`
+	Username     string `yaml:"username"`
+	Password     string `yaml:"password"`
+	RefreshToken string `yaml:"refresh_token"`
+}
+
+func NewClientFromEnv(config Config) (Client, error) {
+	return NewClientFromEnvContext(context.Background(), config)
+}
+
+func NewClientFromEnvContext(ctx context.Context, config Config) (Client, error) {
+	config, err := ConfigFromEnv(config)
+	if err != nil {
+		return Client{}, err
+	}
+
+	return NewClientFromInfoContext(ctx, config)
+}
+
+func ConfigFromEnv(config Config) (Config, error) {
+	environment := os.Getenv("BOSH_ENVIRONMENT")
+	if config.URL == "" && environment == "" {
+		return Config{}, errors.New("BOSH_ENVIRONMENT must be set")
+	}
+
+	cliEnv, err := findCLIEnvironment(environment)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if config.URL == "" {
+		directorURL := environment
+		if cliEnv.URL != "" {
+			directorURL = cliEnv.URL
+		}
+
+		config.URL, err = normalizeDirectorURL(directorURL)
+		if err != nil {
+			return Config{}, err
+		}
+	}
+
+	if config.DirectorCACert == "" {
+		config.DirectorCACert, err = readCACert(os.Getenv("BOSH_CA_CERT"))
+		if err != nil {
+			return Config{}, err
+		}
+	}
+
+	if config.DirectorCACert == "" {
+		config.DirectorCACert = cliEnv.CACert
+	}
+
+	if config.Username == "" && config.Password == "" {
+		config.Username = os.Getenv("BOSH_CLIENT")
+		config.Password = os.Getenv("BOSH_CLIENT_SECRET")
+	}
+
+	if config.Username == "" && config.Password == "" && cliEnv.Username != "" {
+		config.Username = cliEnv.Username
+		config.Password = cliEnv.Password
+		config.UAAPasswordGrant = true
+	}
+
+	if config.Username == "" && config.UAARefreshToken == "" {
+		config.UAARefreshToken = cliEnv.RefreshToken
+	}
+
+	return config, nil
+}
+
+func findCLIEnvironment(environment string) (cliEnvironment, error) {
+	if environment == "" {
+		return cliEnvironment{}, nil
+	}
+
+	path := os.Getenv("BOSH_CONFIG")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return cliEnvironment{}, nil
+		}
+		path = filepath.Join(home, ".bosh", "config")
+	}
+
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cliEnvironment{}, nil
+	}
+	if err != nil {
+		return cliEnvironment{}, err
+	}
+
+	var config cliConfig
+	err = yaml.Unmarshal(contents, &config)
+	if err != nil {
+		return cliEnvironment{}, fmt.Errorf("failed to parse bosh config %s: %s", path, err)
+	}
+
+	environmentURL, err := normalizeDirectorURL(environment)
+	if err != nil {
+		return cliEnvironment{}, err
+	}
+
+	for _, env := range config.Environments {
+		if env.Alias == environment {
+			return env, nil
+		}
+	}
+
+	for _, env := range config.Environments {
+		if envURL, err := normalizeDirectorURL(env.URL); err == nil && envURL == environmentURL {
+			return env, nil
+		}
+	}
+
+	return cliEnvironment{}, nil
+}
+
+func normalizeDirectorURL(environment string) (string, error) {
+	if environment == "" {
+		return "", nil
+	}
+
+	if !strings.Contains(environment, "://") {
+		environment = "https://" + environment
+	}
+
+	directorURL, err := url.Parse(environment)
+	if err != nil {
+		return "", err
+	}
+
+	if directorURL.Port() == "" {
+		directorURL.Host = net.JoinHostPort(directorURL.Hostname(), defaultDirectorPort)
+	}
+
+	return strings.TrimSuffix(directorURL.String(), "/"), nil
+}
+
+func readCACert(caCert string) (string, error) {
+	if caCert == "" || strings.Contains(caCert, "-----BEGIN") {
+		return caCert, nil
+	}
+
+	contents, err := ioutil.ReadFile(caCert)
+	if err != nil {
+		return "", fmt.Errorf("failed to read BOSH_CA_CERT: %s", err)
+	}
+
+	return string(contents), nil
+}
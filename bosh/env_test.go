@@ -0,0 +1,203 @@
+package bosh_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("env", func() {
+	var (
+		tempDir    string
+		configPath string
+		savedEnv   map[string]string
+	)
+
+	BeforeEach(func() {
+		savedEnv = map[string]string{}
+		for _, name := range []string{"BOSH_ENVIRONMENT", "BOSH_CLIENT", "BOSH_CLIENT_SECRET", "BOSH_CA_CERT", "BOSH_CONFIG"} {
+			savedEnv[name] = os.Getenv(name)
+			os.Unsetenv(name)
+		}
+
+		var err error
+		tempDir, err = ioutil.TempDir("", "bosh-config")
+		Expect(err).NotTo(HaveOccurred())
+
+		configPath = filepath.Join(tempDir, "config")
+		os.Setenv("BOSH_CONFIG", configPath)
+	})
+
+	AfterEach(func() {
+		for name, value := range savedEnv {
+			os.Setenv(name, value)
+		}
+		os.RemoveAll(tempDir)
+	})
+
+	Describe("ConfigFromEnv", func() {
+		It("reads the director and credentials from the environment", func() {
+			os.Setenv("BOSH_ENVIRONMENT", "10.0.0.6")
+			os.Setenv("BOSH_CLIENT", "some-client")
+			os.Setenv("BOSH_CLIENT_SECRET", "some-client-secret")
+			os.Setenv("BOSH_CA_CERT", "-----BEGIN CERTIFICATE-----\nsome-ca\n-----END CERTIFICATE-----")
+
+			config, err := bosh.ConfigFromEnv(bosh.Config{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(Equal(bosh.Config{
+				URL:            "https://10.0.0.6:25555",
+				DirectorCACert: "-----BEGIN CERTIFICATE-----\nsome-ca\n-----END CERTIFICATE-----",
+				Username:       "some-client",
+				Password:       "some-client-secret",
+			}))
+		})
+
+		It("reads the CA certificate from a path", func() {
+			caPath := filepath.Join(tempDir, "ca.pem")
+			Expect(ioutil.WriteFile(caPath, []byte("some-ca-from-file"), 0644)).To(Succeed())
+
+			os.Setenv("BOSH_ENVIRONMENT", "https://10.0.0.6:25555")
+			os.Setenv("BOSH_CA_CERT", caPath)
+
+			config, err := bosh.ConfigFromEnv(bosh.Config{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.DirectorCACert).To(Equal("some-ca-from-file"))
+		})
+
+		It("resolves an alias from the bosh CLI config", func() {
+			Expect(ioutil.WriteFile(configPath, []byte(`
+environments:
+- url: https://192.168.50.6:25555
+  alias: vbox
+  ca_cert: some-ca
+  username: admin
+  password: some-password
+- url: https://10.0.0.6:25555
+  alias: aws
+  ca_cert: some-other-ca
+  refresh_token: some-refresh-token
+`), 0644)).To(Succeed())
+
+			os.Setenv("BOSH_ENVIRONMENT", "vbox")
+
+			config, err := bosh.ConfigFromEnv(bosh.Config{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(Equal(bosh.Config{
+				URL:              "https://192.168.50.6:25555",
+				DirectorCACert:   "some-ca",
+				Username:         "admin",
+				Password:         "some-password",
+				UAAPasswordGrant: true,
+			}))
+
+			os.Setenv("BOSH_ENVIRONMENT", "10.0.0.6")
+
+			config, err = bosh.ConfigFromEnv(bosh.Config{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(Equal(bosh.Config{
+				URL:             "https://10.0.0.6:25555",
+				DirectorCACert:  "some-other-ca",
+				UAARefreshToken: "some-refresh-token",
+			}))
+		})
+
+		It("prefers the environment variables over the bosh CLI config", func() {
+			Expect(ioutil.WriteFile(configPath, []byte(`
+environments:
+- url: https://192.168.50.6:25555
+  alias: vbox
+  ca_cert: some-ca
+  username: admin
+  password: some-password
+`), 0644)).To(Succeed())
+
+			os.Setenv("BOSH_ENVIRONMENT", "vbox")
+			os.Setenv("BOSH_CLIENT", "some-client")
+			os.Setenv("BOSH_CLIENT_SECRET", "some-client-secret")
+			os.Setenv("BOSH_CA_CERT", "-----BEGIN CERTIFICATE-----")
+
+			config, err := bosh.ConfigFromEnv(bosh.Config{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(Equal(bosh.Config{
+				URL:            "https://192.168.50.6:25555",
+				DirectorCACert: "-----BEGIN CERTIFICATE-----",
+				Username:       "some-client",
+				Password:       "some-client-secret",
+			}))
+		})
+
+		It("keeps the values already set in the given config", func() {
+			os.Setenv("BOSH_ENVIRONMENT", "10.0.0.6")
+			os.Setenv("BOSH_CLIENT", "some-client")
+
+			config, err := bosh.ConfigFromEnv(bosh.Config{
+				URL:      "https://some-other-director:25555",
+				Username: "some-username",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.URL).To(Equal("https://some-other-director:25555"))
+			Expect(config.Username).To(Equal("some-username"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when BOSH_ENVIRONMENT is not set", func() {
+				_, err := bosh.ConfigFromEnv(bosh.Config{})
+				Expect(err).To(MatchError("BOSH_ENVIRONMENT must be set"))
+			})
+
+			It("returns an error when the CA certificate path cannot be read", func() {
+				os.Setenv("BOSH_ENVIRONMENT", "10.0.0.6")
+				os.Setenv("BOSH_CA_CERT", filepath.Join(tempDir, "missing.pem"))
+
+				_, err := bosh.ConfigFromEnv(bosh.Config{})
+				Expect(err).To(MatchError(ContainSubstring("failed to read BOSH_CA_CERT")))
+			})
+
+			It("returns an error when the bosh CLI config is malformed", func() {
+				Expect(ioutil.WriteFile(configPath, []byte("%%%"), 0644)).To(Succeed())
+				os.Setenv("BOSH_ENVIRONMENT", "vbox")
+
+				_, err := bosh.ConfigFromEnv(bosh.Config{})
+				Expect(err).To(MatchError(ContainSubstring("failed to parse bosh config")))
+			})
+		})
+	})
+
+	Describe("NewClientFromEnv", func() {
+		It("builds a client for the director named in the environment", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/info":
+					w.Write([]byte(`{"user_authentication": {"type": "basic", "options": {}}}`))
+				case "/locks":
+					username, password, ok := r.BasicAuth()
+					Expect(ok).To(BeTrue())
+					Expect(username).To(Equal("some-client"))
+					Expect(password).To(Equal("some-client-secret"))
+
+					w.Write([]byte(`[]`))
+				default:
+					Fail("could not match any URL endpoints")
+				}
+			}))
+			defer server.Close()
+
+			os.Setenv("BOSH_ENVIRONMENT", server.URL)
+			os.Setenv("BOSH_CLIENT", "some-client")
+			os.Setenv("BOSH_CLIENT_SECRET", "some-client-secret")
+
+			client, err := bosh.NewClientFromEnv(bosh.Config{})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.Locks()
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
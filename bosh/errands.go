@@ -0,0 +1,167 @@
+package bosh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type Errand struct {
+	Name string `json:"name"`
+}
+
+type ErrandOptions struct {
+	KeepAlive   bool
+	WhenChanged bool
+	Instances   []InstanceSelector
+}
+
+type ErrandResult struct {
+	ErrandName      string
+	Instance        InstanceSelector
+	ExitCode        int
+	Stdout          string
+	Stderr          string
+	LogsBlobstoreID string
+}
+
+func (r ErrandResult) Succeeded() bool {
+	return r.ExitCode == 0
+}
+
+func (c Client) Errands(deployment string) ([]Errand, error) {
+	return c.ErrandsContext(context.Background(), deployment)
+}
+
+func (c Client) ErrandsContext(ctx context.Context, deployment string) ([]Errand, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/deployments/%s/errands", c.config.URL, deployment), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.makeRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := bodyReader(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, newDirectorError(response, body)
+	}
+
+	var errands []Errand
+	err = json.Unmarshal(body, &errands)
+	if err != nil {
+		return nil, err
+	}
+
+	return errands, nil
+}
+
+func (c Client) RunErrand(deployment, errand string, options ErrandOptions) ([]ErrandResult, error) {
+	return c.RunErrandContext(context.Background(), deployment, errand, options)
+}
+
+func (c Client) RunErrandContext(ctx context.Context, deployment, errand string, options ErrandOptions) ([]ErrandResult, error) {
+	type errandInstance struct {
+		Group string `json:"group"`
+		ID    string `json:"id,omitempty"`
+	}
+
+	instances := []errandInstance{}
+	for _, selector := range options.Instances {
+		instances = append(instances, errandInstance{Group: selector.Group, ID: selector.ID})
+	}
+
+	payload, err := json.Marshal(struct {
+		KeepAlive   bool             `json:"keep-alive"`
+		WhenChanged bool             `json:"when-changed"`
+		Instances   []errandInstance `json:"instances"`
+	}{
+		KeepAlive:   options.KeepAlive,
+		WhenChanged: options.WhenChanged,
+		Instances:   instances,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/deployments/%s/errands/%s/runs", c.config.URL, deployment, errand), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := c.makeRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := bodyReader(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusFound {
+		return nil, newDirectorError(response, body)
+	}
+
+	taskId, err := c.checkTaskStatus(ctx, response.Header.Get("Location"))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.taskOutputFrom(ctx, taskId, "result", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseErrandResults(result)
+}
+
+func parseErrandResults(output []byte) ([]ErrandResult, error) {
+	results := []ErrandResult{}
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var result struct {
+			ErrandName string `json:"errand_name"`
+			Instance   struct {
+				Group string `json:"group"`
+				ID    string `json:"id"`
+			} `json:"instance"`
+			ExitCode int    `json:"exit_code"`
+			Stdout   string `json:"stdout"`
+			Stderr   string `json:"stderr"`
+			Logs     struct {
+				BlobstoreID string `json:"blobstore_id"`
+			} `json:"logs"`
+		}
+		err := json.Unmarshal(line, &result)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, ErrandResult{
+			ErrandName:      result.ErrandName,
+			Instance:        InstanceSelector{Group: result.Instance.Group, ID: result.Instance.ID},
+			ExitCode:        result.ExitCode,
+			Stdout:          result.Stdout,
+			Stderr:          result.Stderr,
+			LogsBlobstoreID: result.Logs.BlobstoreID,
+		})
+	}
+
+	return results, nil
+}
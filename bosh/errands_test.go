@@ -0,0 +1,207 @@
+package bosh_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("errands", func() {
+	Describe("Errands", func() {
+		It("lists the errands of a deployment", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.Method).To(Equal("GET"))
+				Expect(r.URL.Path).To(Equal("/deployments/some-deployment/errands"))
+
+				username, password, ok := r.BasicAuth()
+				Expect(ok).To(BeTrue())
+				Expect(username).To(Equal("some-username"))
+				Expect(password).To(Equal("some-password"))
+
+				w.Write([]byte(`[{"name":"smoke-tests"},{"name":"acceptance-tests"}]`))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:      server.URL,
+				Username: "some-username",
+				Password: "some-password",
+			})
+
+			errands, err := client.Errands("some-deployment")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errands).To(Equal([]bosh.Errand{
+				{Name: "smoke-tests"},
+				{Name: "acceptance-tests"},
+			}))
+		})
+
+		It("returns an error on an unexpected status code", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"code":70000,"description":"Deployment 'some-deployment' doesn't exist"}`))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			_, err := client.Errands("some-deployment")
+			Expect(errors.Is(err, bosh.ErrDeploymentNotFound)).To(BeTrue())
+		})
+
+		It("returns an error when the response is not JSON", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("%%%"))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			_, err := client.Errands("some-deployment")
+			Expect(err).To(MatchError(ContainSubstring("invalid character")))
+		})
+	})
+
+	Describe("RunErrand", func() {
+		var (
+			server  *httptest.Server
+			payload map[string]interface{}
+			result  string
+		)
+
+		BeforeEach(func() {
+			payload = nil
+			result = `{"instance":{"group":"smoke-tests","id":"some-uuid"},"errand_name":"smoke-tests","exit_code":0,"stdout":"all good\n","stderr":"","logs":{"blobstore_id":"some-blob"}}
+{"instance":{"group":"smoke-tests","id":"other-uuid"},"errand_name":"smoke-tests","exit_code":1,"stdout":"","stderr":"boom\n","logs":{"blobstore_id":"other-blob"}}
+`
+
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/deployments/some-deployment/errands/smoke-tests/runs":
+					Expect(r.Method).To(Equal("POST"))
+					Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+					Expect(json.NewDecoder(r.Body).Decode(&payload)).To(Succeed())
+
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/3", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/3":
+					w.Write([]byte(`{"id": 3, "state": "done"}`))
+				case "/tasks/3/output":
+					Expect(r.URL.RawQuery).To(Equal("type=result"))
+					w.Write([]byte(result))
+				default:
+					Fail(fmt.Sprintf("unhandled request to %s", r.URL.Path))
+				}
+			}))
+		})
+
+		It("runs the errand and returns the result for each instance", func() {
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			results, err := client.RunErrand("some-deployment", "smoke-tests", bosh.ErrandOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(payload).To(Equal(map[string]interface{}{
+				"keep-alive":   false,
+				"when-changed": false,
+				"instances":    []interface{}{},
+			}))
+
+			Expect(results).To(Equal([]bosh.ErrandResult{
+				{
+					ErrandName:      "smoke-tests",
+					Instance:        bosh.InstanceByID("smoke-tests", "some-uuid"),
+					ExitCode:        0,
+					Stdout:          "all good\n",
+					LogsBlobstoreID: "some-blob",
+				},
+				{
+					ErrandName:      "smoke-tests",
+					Instance:        bosh.InstanceByID("smoke-tests", "other-uuid"),
+					ExitCode:        1,
+					Stderr:          "boom\n",
+					LogsBlobstoreID: "other-blob",
+				},
+			}))
+			Expect(results[0].Succeeded()).To(BeTrue())
+			Expect(results[1].Succeeded()).To(BeFalse())
+		})
+
+		It("sends the keep-alive, when-changed and instance filters", func() {
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			_, err := client.RunErrand("some-deployment", "smoke-tests", bosh.ErrandOptions{
+				KeepAlive:   true,
+				WhenChanged: true,
+				Instances: []bosh.InstanceSelector{
+					bosh.InstanceGroup("smoke-tests"),
+					bosh.InstanceByID("api", "some-uuid"),
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(payload).To(Equal(map[string]interface{}{
+				"keep-alive":   true,
+				"when-changed": true,
+				"instances": []interface{}{
+					map[string]interface{}{"group": "smoke-tests"},
+					map[string]interface{}{"group": "api", "id": "some-uuid"},
+				},
+			}))
+		})
+
+		It("returns no results when the errand was skipped", func() {
+			result = ""
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			results, err := client.RunErrand("some-deployment", "smoke-tests", bosh.ErrandOptions{WhenChanged: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(BeEmpty())
+		})
+
+		Context("failure cases", func() {
+			It("returns an error on an unexpected status code", func() {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte("More Info"))
+				}))
+
+				client := bosh.NewClient(bosh.Config{
+					URL: server.URL,
+				})
+
+				_, err := client.RunErrand("some-deployment", "smoke-tests", bosh.ErrandOptions{})
+				Expect(err).To(MatchError("unexpected response 400 Bad Request:\nMore Info"))
+			})
+
+			It("returns an error when the task result is malformed", func() {
+				result = "%%%"
+
+				client := bosh.NewClient(bosh.Config{
+					URL:                 server.URL,
+					TaskPollingInterval: time.Nanosecond,
+				})
+
+				_, err := client.RunErrand("some-deployment", "smoke-tests", bosh.ErrandOptions{})
+				Expect(err).To(MatchError(ContainSubstring("invalid character")))
+			})
+		})
+	})
+})
@@ -0,0 +1,178 @@
+package bosh
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrDeploymentNotFound = errors.New("deployment not found")
+	ErrReleaseNotFound    = errors.New("release not found")
+	ErrReleaseInUse       = errors.New("release is in use")
+	ErrStemcellNotFound   = errors.New("stemcell not found")
+	ErrStemcellInUse      = errors.New("stemcell is in use")
+	ErrLockTimeout        = errors.New("timed out acquiring lock")
+	ErrConfigNotFound     = errors.New("config not found")
+)
+
+var directorErrorCodes = map[int]error{
+	30005: ErrReleaseNotFound,
+	30006: ErrReleaseNotFound,
+	30007: ErrReleaseInUse,
+	30008: ErrReleaseInUse,
+	50003: ErrStemcellNotFound,
+	50004: ErrStemcellInUse,
+	70000: ErrDeploymentNotFound,
+}
+
+var directorErrorDescriptions = map[string]error{
+	"Failed to acquire lock": ErrLockTimeout,
+}
+
+type DirectorError struct {
+	StatusCode  int
+	Code        int
+	Description string
+	Method      string
+	URL         string
+	Body        []byte
+
+	message string
+	kind    error
+}
+
+func newDirectorError(response *http.Response, body []byte) DirectorError {
+	directorErr := DirectorError{
+		StatusCode: response.StatusCode,
+		Body:       body,
+	}
+
+	if response.Request != nil {
+		directorErr.Method = response.Request.Method
+		directorErr.URL = response.Request.URL.String()
+	}
+
+	var payload struct {
+		Code        int
+		Description string
+	}
+	if json.Unmarshal(body, &payload) == nil {
+		directorErr.Code = payload.Code
+		directorErr.Description = payload.Description
+	}
+
+	return directorErr
+}
+
+func (e DirectorError) Error() string {
+	switch {
+	case e.message != "":
+		return e.message
+	case e.StatusCode == 0:
+		return fmt.Sprintf("director error %d: %s", e.Code, e.Description)
+	case len(e.Body) == 0:
+		return fmt.Sprintf("unexpected response %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	default:
+		return fmt.Sprintf("unexpected response %d %s:\n%s", e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+	}
+}
+
+func (e DirectorError) Is(target error) bool {
+	if e.kind != nil && e.kind == target {
+		return true
+	}
+
+	kind := directorErrorKind(e.Code, e.Description)
+	return kind != nil && kind == target
+}
+
+func directorErrorKind(code int, description string) error {
+	if kind, ok := directorErrorCodes[code]; ok {
+		return kind
+	}
+
+	for prefix, kind := range directorErrorDescriptions {
+		if strings.HasPrefix(description, prefix) {
+			return kind
+		}
+	}
+
+	return nil
+}
+
+type TaskFailedError struct {
+	TaskID   int
+	State    string
+	Result   string
+	Failures []TaskOutput
+}
+
+func newTaskFailedError(task Task, taskOutputs []TaskOutput) TaskFailedError {
+	taskErr := TaskFailedError{
+		TaskID: task.Id,
+		State:  task.State,
+		Result: task.Result,
+	}
+
+	for _, taskOutput := range taskOutputs {
+		if taskOutput.State == "failed" || taskOutput.Error != (TaskError{}) {
+			taskErr.Failures = append(taskErr.Failures, taskOutput)
+		}
+	}
+
+	return taskErr
+}
+
+func (e TaskFailedError) Error() string {
+	message := fmt.Sprintf("bosh task %d failed with an %s status %q", e.TaskID, e.State, e.Result)
+	if len(e.Failures) == 0 {
+		return message
+	}
+
+	lines := []string{message + ":"}
+	for _, failure := range e.Failures {
+		lines = append(lines, "  - "+describeTaskFailure(failure))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (e TaskFailedError) Unwrap() []error {
+	var errs []error
+	for _, failure := range e.Failures {
+		if failure.Error != (TaskError{}) {
+			errs = append(errs, failure.Error)
+		}
+	}
+
+	return errs
+}
+
+func describeTaskFailure(failure TaskOutput) string {
+	var parts []string
+	if failure.Stage != "" {
+		parts = append(parts, failure.Stage)
+	}
+
+	if len(failure.Tags) > 0 {
+		parts = append(parts, fmt.Sprintf("[%s]", strings.Join(failure.Tags, ", ")))
+	}
+
+	if failure.Task != "" {
+		parts = append(parts, failure.Task)
+	}
+
+	reason := failure.Data.Error
+	if failure.Error != (TaskError{}) {
+		reason = failure.Error.Error()
+	}
+
+	if len(parts) == 0 {
+		return reason
+	}
+
+	return fmt.Sprintf("%s: %s", strings.Join(parts, " "), reason)
+}
@@ -0,0 +1,182 @@
+package bosh_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DirectorError", func() {
+	It("carries the status, director error code, description and request", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": 70000, "description": "Deployment 'some-deployment' doesn't exist"}`))
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL: server.URL,
+		})
+
+		_, err := client.DownloadManifest("some-deployment")
+		Expect(err).To(MatchError("unexpected response 404 Not Found:\n{\"code\": 70000, \"description\": \"Deployment 'some-deployment' doesn't exist\"}"))
+
+		var directorErr bosh.DirectorError
+		Expect(errors.As(err, &directorErr)).To(BeTrue())
+		Expect(directorErr.StatusCode).To(Equal(http.StatusNotFound))
+		Expect(directorErr.Code).To(Equal(70000))
+		Expect(directorErr.Description).To(Equal("Deployment 'some-deployment' doesn't exist"))
+		Expect(directorErr.Method).To(Equal("GET"))
+		Expect(directorErr.URL).To(Equal(server.URL + "/deployments/some-deployment"))
+
+		Expect(errors.Is(err, bosh.ErrDeploymentNotFound)).To(BeTrue())
+		Expect(errors.Is(err, bosh.ErrReleaseNotFound)).To(BeFalse())
+	})
+
+	It("does not match any sentinel for unknown error codes", func() {
+		err := bosh.DirectorError{Code: 12345, Description: "something else"}
+		Expect(err).To(MatchError("director error 12345: something else"))
+
+		for _, sentinel := range []error{
+			bosh.ErrDeploymentNotFound,
+			bosh.ErrReleaseNotFound,
+			bosh.ErrReleaseInUse,
+			bosh.ErrStemcellNotFound,
+			bosh.ErrStemcellInUse,
+			bosh.ErrLockTimeout,
+		} {
+			Expect(errors.Is(err, sentinel)).To(BeFalse())
+		}
+	})
+
+	It("matches sentinels for failed tasks", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/releases/some-release":
+				w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/1", r.Host))
+				w.WriteHeader(http.StatusFound)
+			case "/tasks/1":
+				w.Write([]byte(`{"id": 1, "state": "error", "result": "Release 'some-release' is still in use"}`))
+			case "/tasks/1/output":
+				w.Write([]byte(`{"error": {"code": 30007, "message": "Release 'some-release' is still in use"}}`))
+			default:
+				Fail("could not match any URL endpoints")
+			}
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		err := client.DeleteRelease("some-release", "1")
+		Expect(err).To(MatchError("bosh task 1 failed with an error status \"Release 'some-release' is still in use\":\n  - task error: 30007 has occurred: Release 'some-release' is still in use"))
+		Expect(errors.Is(err, bosh.ErrReleaseInUse)).To(BeTrue())
+
+		var directorErr bosh.DirectorError
+		Expect(errors.As(err, &directorErr)).To(BeTrue())
+		Expect(directorErr.Code).To(Equal(30007))
+	})
+
+	It("matches lock timeouts by description", func() {
+		err := bosh.TaskError{Code: 100, Message: "Failed to acquire lock for lock:deployment:some-deployment uid: some-uid. Locking task id is 4"}
+		Expect(errors.Is(err, bosh.ErrLockTimeout)).To(BeTrue())
+	})
+
+	It("matches not found sentinels for releases", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL: server.URL,
+		})
+
+		_, err := client.Release("some-release")
+		Expect(err).To(MatchError("release some-release could not be found"))
+		Expect(errors.Is(err, bosh.ErrReleaseNotFound)).To(BeTrue())
+	})
+
+	It("matches in use sentinels for stemcells", func() {
+		Expect(errors.Is(bosh.InUse{Use: true}, bosh.ErrStemcellInUse)).To(BeTrue())
+		Expect(errors.Is(bosh.InUse{}, bosh.ErrStemcellInUse)).To(BeFalse())
+	})
+})
+
+var _ = Describe("TaskFailedError", func() {
+	It("collects every failed event of the task", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/deployments":
+				w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/1", r.Host))
+				w.WriteHeader(http.StatusFound)
+			case "/tasks/1":
+				w.Write([]byte(`{"id": 1, "state": "error", "result": "some-result"}`))
+			case "/tasks/1/output":
+				w.Write([]byte(`
+					{"time": 1, "stage": "Updating instance", "tags": ["api"], "total": 2, "task": "api/0", "index": 1, "state": "started", "progress": 0}
+					{"time": 2, "stage": "Updating instance", "tags": ["api"], "total": 2, "task": "api/0", "index": 1, "state": "failed", "progress": 100, "data": {"error": "api/0 is not running after update"}}
+					{"time": 3, "stage": "Updating instance", "tags": ["api"], "total": 2, "task": "api/1", "index": 2, "state": "failed", "progress": 100, "data": {"error": "api/1 is not running after update"}}
+					{"time": 4, "error": {"code": 400007, "message": "some-task-error"}}
+				`))
+			default:
+				Fail("could not match any URL endpoints")
+			}
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		_, err := client.Deploy([]byte("some-yaml"))
+		Expect(err).To(MatchError("bosh task 1 failed with an error status \"some-result\":\n" +
+			"  - Updating instance [api] api/0: api/0 is not running after update\n" +
+			"  - Updating instance [api] api/1: api/1 is not running after update\n" +
+			"  - task error: 400007 has occurred: some-task-error"))
+
+		var taskErr bosh.TaskFailedError
+		Expect(errors.As(err, &taskErr)).To(BeTrue())
+		Expect(taskErr.TaskID).To(Equal(1))
+		Expect(taskErr.State).To(Equal("error"))
+		Expect(taskErr.Result).To(Equal("some-result"))
+		Expect(taskErr.Failures).To(HaveLen(3))
+		Expect(taskErr.Failures[1].Task).To(Equal("api/1"))
+		Expect(taskErr.Failures[1].Tags).To(Equal([]string{"api"}))
+		Expect(taskErr.Failures[1].Data.Error).To(Equal("api/1 is not running after update"))
+
+		var eventErr bosh.TaskError
+		Expect(errors.As(err, &eventErr)).To(BeTrue())
+		Expect(eventErr.Code).To(Equal(400007))
+	})
+
+	It("does not panic when the event log is empty", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/deployments":
+				w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/1", r.Host))
+				w.WriteHeader(http.StatusFound)
+			case "/tasks/1":
+				w.Write([]byte(`{"id": 1, "state": "errored", "result": "some-result"}`))
+			case "/tasks/1/output":
+				w.WriteHeader(http.StatusOK)
+			default:
+				Fail("could not match any URL endpoints")
+			}
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		_, err := client.Deploy([]byte("some-yaml"))
+		Expect(err).To(MatchError("bosh task 1 failed with an errored status \"some-result\""))
+	})
+})
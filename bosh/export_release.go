@@ -2,6 +2,7 @@ package bosh
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,6 +18,10 @@ type exportReleaseRequest struct {
 }
 
 func (c Client) ExportRelease(deploymentName, releaseName, releaseVersion, stemcellOS, stemcellVersion string) (string, error) {
+	return c.ExportReleaseContext(context.Background(), deploymentName, releaseName, releaseVersion, stemcellOS, stemcellVersion)
+}
+
+func (c Client) ExportReleaseContext(ctx context.Context, deploymentName, releaseName, releaseVersion, stemcellOS, stemcellVersion string) (string, error) {
 	content := exportReleaseRequest{
 		DeploymentName:  deploymentName,
 		ReleaseName:     releaseName,
@@ -30,7 +35,7 @@ func (c Client) ExportRelease(deploymentName, releaseName, releaseVersion, stemc
 		return "", err
 	}
 
-	request, err := http.NewRequest("POST", fmt.Sprintf("%s/releases/export", c.config.URL), bytes.NewBuffer(requestBody))
+	request, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/releases/export", c.config.URL), bytes.NewBuffer(requestBody))
 	if err != nil {
 		return "", err
 	}
@@ -42,12 +47,12 @@ func (c Client) ExportRelease(deploymentName, releaseName, releaseVersion, stemc
 		return "", err
 	}
 
-	taskId, err := c.checkTaskStatus(response.Header.Get("Location"))
+	taskId, err := c.checkTaskStatus(ctx, response.Header.Get("Location"))
 	if err != nil {
 		return "", err
 	}
 
-	taskResult, err := c.TaskResult(taskId)
+	taskResult, err := c.TaskResultContext(ctx, taskId)
 	if err != nil {
 		return "", err
 	}
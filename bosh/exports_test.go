@@ -3,6 +3,7 @@ package bosh
 import (
 	"io"
 	"io/ioutil"
+	"time"
 )
 
 func SetBodyReader(r func(io.Reader) ([]byte, error)) {
@@ -12,3 +13,11 @@ func SetBodyReader(r func(io.Reader) ([]byte, error)) {
 func ResetBodyReader() {
 	bodyReader = ioutil.ReadAll
 }
+
+func SetUploadProgressInterval(interval time.Duration) {
+	uploadProgressInterval = interval
+}
+
+func ResetUploadProgressInterval() {
+	uploadProgressInterval = time.Second
+}
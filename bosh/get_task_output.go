@@ -2,6 +2,7 @@ package bosh
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -17,6 +18,11 @@ type TaskOutput struct {
 	Index    int
 	State    string
 	Progress int
+	Data     TaskOutputData
+}
+
+type TaskOutputData struct {
+	Error string
 }
 
 type TaskError struct {
@@ -32,8 +38,19 @@ func (te TaskError) ErrorCode() int {
 	return te.Code
 }
 
+func (te TaskError) Unwrap() error {
+	return DirectorError{
+		Code:        te.Code,
+		Description: te.Message,
+	}
+}
+
 func (c Client) GetTaskOutput(taskId int) ([]TaskOutput, error) {
-	request, err := http.NewRequest("GET", fmt.Sprintf("%s/tasks/%d/output?type=event", c.config.URL, taskId), nil)
+	return c.GetTaskOutputContext(context.Background(), taskId)
+}
+
+func (c Client) GetTaskOutputContext(ctx context.Context, taskId int) ([]TaskOutput, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/tasks/%d/output?type=event", c.config.URL, taskId), nil)
 	if err != nil {
 		return []TaskOutput{}, err
 	}
@@ -50,7 +67,7 @@ func (c Client) GetTaskOutput(taskId int) ([]TaskOutput, error) {
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		return []TaskOutput{}, fmt.Errorf("unexpected response %d %s:\n%s", response.StatusCode, http.StatusText(response.StatusCode), body)
+		return []TaskOutput{}, newDirectorError(response, body)
 	}
 
 	body = bytes.TrimSpace(body)
@@ -58,6 +75,11 @@ func (c Client) GetTaskOutput(taskId int) ([]TaskOutput, error) {
 
 	var taskOutputs []TaskOutput
 	for _, part := range parts {
+		part = bytes.TrimSpace(part)
+		if len(part) == 0 {
+			continue
+		}
+
 		var taskOutput TaskOutput
 		err = json.Unmarshal(part, &taskOutput)
 		if err != nil {
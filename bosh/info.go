@@ -1,18 +1,50 @@
 package bosh
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 )
 
 type DirectorInfo struct {
-	UUID string
-	CPI  string
+	Name               string
+	UUID               string
+	Version            string
+	CPI                string
+	UserAuthentication UserAuthentication `json:"user_authentication"`
+	Features           map[string]DirectorFeature
+}
+
+type UserAuthentication struct {
+	Type    string
+	Options map[string]interface{}
+}
+
+type DirectorFeature struct {
+	Status bool
+	Extras map[string]interface{}
+}
+
+func (i DirectorInfo) UAA() bool {
+	return i.UserAuthentication.Type == "uaa"
+}
+
+func (i DirectorInfo) FeatureEnabled(name string) bool {
+	return i.Features[name].Status
 }
 
 func (c Client) Info() (DirectorInfo, error) {
-	response, err := client.Get(fmt.Sprintf("%s/info", c.config.URL))
+	return c.InfoContext(context.Background())
+}
+
+func (c Client) InfoContext(ctx context.Context) (DirectorInfo, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/info", c.config.URL), nil)
+	if err != nil {
+		return DirectorInfo{}, err
+	}
+
+	response, err := c.httpClient.Do(request)
 	if err != nil {
 		return DirectorInfo{}, err
 	}
@@ -24,7 +56,7 @@ func (c Client) Info() (DirectorInfo, error) {
 		}
 		defer response.Body.Close()
 
-		return DirectorInfo{}, fmt.Errorf("unexpected response %d %s:\n%s", response.StatusCode, http.StatusText(response.StatusCode), body)
+		return DirectorInfo{}, newDirectorError(response, body)
 	}
 
 	info := DirectorInfo{}
@@ -35,3 +67,21 @@ func (c Client) Info() (DirectorInfo, error) {
 
 	return info, nil
 }
+
+func NewClientFromInfo(config Config) (Client, error) {
+	return NewClientFromInfoContext(context.Background(), config)
+}
+
+func NewClientFromInfoContext(ctx context.Context, config Config) (Client, error) {
+	info, err := NewClient(config).InfoContext(ctx)
+	if err != nil {
+		return Client{}, err
+	}
+
+	config.UAA = info.UAA()
+	if config.UAA && config.UAAURL == "" {
+		config.UAAURL, _ = info.UserAuthentication.Options["url"].(string)
+	}
+
+	return NewClient(config), nil
+}
@@ -2,6 +2,7 @@ package bosh_test
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -36,6 +37,121 @@ var _ = Describe("Info", func() {
 		}))
 	})
 
+	It("decodes the full director info document", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{
+				"name": "some-director",
+				"uuid": "some-director-uuid",
+				"version": "262.3.0 (00000000)",
+				"user": null,
+				"cpi": "some-cpi",
+				"user_authentication": {"type": "uaa", "options": {"url": "https://10.0.0.6:8443"}},
+				"features": {
+					"dns": {"status": true, "extras": {"domain_name": "bosh"}},
+					"compiled_package_cache": {"status": false, "extras": {"provider": null}},
+					"snapshots": {"status": false},
+					"config_server": {"status": true, "extras": {"urls": ["https://10.0.0.6:8080/api/"]}}
+				}
+			}`))
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL: server.URL,
+		})
+
+		info, err := client.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info).To(Equal(bosh.DirectorInfo{
+			Name:    "some-director",
+			UUID:    "some-director-uuid",
+			Version: "262.3.0 (00000000)",
+			CPI:     "some-cpi",
+			UserAuthentication: bosh.UserAuthentication{
+				Type:    "uaa",
+				Options: map[string]interface{}{"url": "https://10.0.0.6:8443"},
+			},
+			Features: map[string]bosh.DirectorFeature{
+				"dns":                    {Status: true, Extras: map[string]interface{}{"domain_name": "bosh"}},
+				"compiled_package_cache": {Status: false, Extras: map[string]interface{}{"provider": nil}},
+				"snapshots":              {Status: false},
+				"config_server":          {Status: true, Extras: map[string]interface{}{"urls": []interface{}{"https://10.0.0.6:8080/api/"}}},
+			},
+		}))
+
+		Expect(info.UAA()).To(BeTrue())
+		Expect(info.FeatureEnabled("dns")).To(BeTrue())
+		Expect(info.FeatureEnabled("snapshots")).To(BeFalse())
+		Expect(info.FeatureEnabled("some-unknown-feature")).To(BeFalse())
+	})
+
+	Describe("NewClientFromInfo", func() {
+		It("uses basic auth when the director does", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/info":
+					w.Write([]byte(`{"user_authentication": {"type": "basic", "options": {}}}`))
+				case "/locks":
+					username, password, ok := r.BasicAuth()
+					Expect(ok).To(BeTrue())
+					Expect(username).To(Equal("some-username"))
+					Expect(password).To(Equal("some-password"))
+
+					w.Write([]byte(`[]`))
+				default:
+					Fail("could not match any URL endpoints")
+				}
+			}))
+
+			client, err := bosh.NewClientFromInfo(bosh.Config{
+				URL:      server.URL,
+				Username: "some-username",
+				Password: "some-password",
+				UAA:      true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(client.GetConfig().UAA).To(BeFalse())
+
+			_, err = client.Locks()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("uses the advertised UAA when the director does", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/info":
+					w.Write([]byte(fmt.Sprintf(`{"user_authentication": {"type": "uaa", "options": {"url": "http://%s/uaa"}}}`, r.Host)))
+				case "/uaa/oauth/token":
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"access_token": "some-token", "token_type": "bearer", "expires_in": 3600}`))
+				case "/locks":
+					Expect(r.Header.Get("Authorization")).To(Equal("Bearer some-token"))
+					w.Write([]byte(`[]`))
+				default:
+					Fail("could not match any URL endpoints")
+				}
+			}))
+
+			client, err := bosh.NewClientFromInfo(bosh.Config{
+				URL:      server.URL,
+				Username: "some-client",
+				Password: "some-client-secret",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(client.GetConfig().UAA).To(BeTrue())
+			Expect(client.GetConfig().UAAURL).To(Equal(server.URL + "/uaa"))
+
+			_, err = client.Locks()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns an error when the info cannot be fetched", func() {
+			_, err := bosh.NewClientFromInfo(bosh.Config{
+				URL: "banana://example.com",
+			})
+			Expect(err).To(MatchError(ContainSubstring("unsupported protocol")))
+		})
+	})
+
 	Context("failure cases", func() {
 		It("should error on malformed json", func() {
 			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
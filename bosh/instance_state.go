@@ -0,0 +1,137 @@
+package bosh
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type InstanceSelector struct {
+	Group string
+	ID    string
+}
+
+func AllInstances() InstanceSelector {
+	return InstanceSelector{}
+}
+
+func InstanceGroup(group string) InstanceSelector {
+	return InstanceSelector{Group: group}
+}
+
+func InstanceByID(group, id string) InstanceSelector {
+	return InstanceSelector{Group: group, ID: id}
+}
+
+func InstanceByIndex(group string, index int) InstanceSelector {
+	return InstanceSelector{Group: group, ID: strconv.Itoa(index)}
+}
+
+func (s InstanceSelector) path() string {
+	switch {
+	case s.Group == "":
+		return "*"
+	case s.ID == "":
+		return url.PathEscape(s.Group)
+	default:
+		return fmt.Sprintf("%s/%s", url.PathEscape(s.Group), url.PathEscape(s.ID))
+	}
+}
+
+type InstanceStateOptions struct {
+	SkipDrain   bool
+	Fix         bool
+	Canaries    string
+	MaxInFlight string
+}
+
+func (o InstanceStateOptions) query(state string) url.Values {
+	query := url.Values{}
+	query.Set("state", state)
+	if o.SkipDrain {
+		query.Set("skip_drain", "true")
+	}
+
+	if o.Fix {
+		query.Set("fix", "true")
+	}
+
+	if o.Canaries != "" {
+		query.Set("canaries", o.Canaries)
+	}
+
+	if o.MaxInFlight != "" {
+		query.Set("max_in_flight", o.MaxInFlight)
+	}
+
+	return query
+}
+
+func (c Client) StartInstances(deployment string, selector InstanceSelector, options InstanceStateOptions) error {
+	return c.StartInstancesContext(context.Background(), deployment, selector, options)
+}
+
+func (c Client) StartInstancesContext(ctx context.Context, deployment string, selector InstanceSelector, options InstanceStateOptions) error {
+	return c.changeInstanceState(ctx, deployment, selector, options.query("started"))
+}
+
+func (c Client) StopInstances(deployment string, selector InstanceSelector, options InstanceStateOptions) error {
+	return c.StopInstancesContext(context.Background(), deployment, selector, options)
+}
+
+func (c Client) StopInstancesContext(ctx context.Context, deployment string, selector InstanceSelector, options InstanceStateOptions) error {
+	return c.changeInstanceState(ctx, deployment, selector, options.query("stopped"))
+}
+
+func (c Client) HardStopInstances(deployment string, selector InstanceSelector, options InstanceStateOptions) error {
+	return c.HardStopInstancesContext(context.Background(), deployment, selector, options)
+}
+
+func (c Client) HardStopInstancesContext(ctx context.Context, deployment string, selector InstanceSelector, options InstanceStateOptions) error {
+	return c.changeInstanceState(ctx, deployment, selector, options.query("detached"))
+}
+
+func (c Client) RecreateInstances(deployment string, selector InstanceSelector, options InstanceStateOptions) error {
+	return c.RecreateInstancesContext(context.Background(), deployment, selector, options)
+}
+
+func (c Client) RecreateInstancesContext(ctx context.Context, deployment string, selector InstanceSelector, options InstanceStateOptions) error {
+	return c.changeInstanceState(ctx, deployment, selector, options.query("recreate"))
+}
+
+func (c Client) RestartInstances(deployment string, selector InstanceSelector, options InstanceStateOptions) error {
+	return c.RestartInstancesContext(context.Background(), deployment, selector, options)
+}
+
+func (c Client) RestartInstancesContext(ctx context.Context, deployment string, selector InstanceSelector, options InstanceStateOptions) error {
+	return c.changeInstanceState(ctx, deployment, selector, options.query("restart"))
+}
+
+func (c Client) changeInstanceState(ctx context.Context, deployment string, selector InstanceSelector, query url.Values) error {
+	request, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/deployments/%s/jobs/%s?%s", c.config.URL, deployment, selector.path(), query.Encode()), bytes.NewBuffer([]byte{}))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "text/yaml")
+	response, err := c.makeRequest(request)
+	if err != nil {
+		return err
+	}
+
+	body, err := bodyReader(response.Body)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusFound {
+		return newDirectorError(response, body)
+	}
+
+	_, err = c.checkTaskStatus(ctx, response.Header.Get("Location"))
+	return err
+}
@@ -0,0 +1,148 @@
+package bosh_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("instance state changes", func() {
+	It("addresses the whole deployment, an instance group or a single instance", func() {
+		var paths []string
+		var taskPolls int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(username).To(Equal("some-username"))
+			Expect(password).To(Equal("some-password"))
+
+			switch r.URL.Path {
+			case "/tasks/1":
+				taskPolls++
+				w.Write([]byte(`{"id": 1, "state": "done"}`))
+			default:
+				Expect(r.Method).To(Equal("PUT"))
+				Expect(r.Header.Get("Content-Type")).To(Equal("text/yaml"))
+				Expect(r.URL.RawQuery).To(Equal("state=stopped"))
+
+				paths = append(paths, r.URL.EscapedPath())
+
+				w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/1", r.Host))
+				w.WriteHeader(http.StatusFound)
+			}
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			Username:            "some-username",
+			Password:            "some-password",
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		Expect(client.StopInstances("some-deployment", bosh.AllInstances(), bosh.InstanceStateOptions{})).To(Succeed())
+		Expect(client.StopInstances("some-deployment", bosh.InstanceGroup("api"), bosh.InstanceStateOptions{})).To(Succeed())
+		Expect(client.StopInstances("some-deployment", bosh.InstanceByIndex("api", 2), bosh.InstanceStateOptions{})).To(Succeed())
+		Expect(client.StopInstances("some-deployment", bosh.InstanceByID("api", "some-uuid"), bosh.InstanceStateOptions{})).To(Succeed())
+
+		Expect(paths).To(Equal([]string{
+			"/deployments/some-deployment/jobs/*",
+			"/deployments/some-deployment/jobs/api",
+			"/deployments/some-deployment/jobs/api/2",
+			"/deployments/some-deployment/jobs/api/some-uuid",
+		}))
+		Expect(taskPolls).To(Equal(4))
+	})
+
+	DescribeTable("requests the state for each action",
+		func(action func(bosh.Client) error, expectedPath, expectedQuery string) {
+			var rawQuery string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case expectedPath:
+					Expect(r.Method).To(Equal("PUT"))
+					rawQuery = r.URL.RawQuery
+
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/1", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/1":
+					w.Write([]byte(`{"id": 1, "state": "done"}`))
+				default:
+					Fail("could not match any URL endpoints")
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			Expect(action(client)).To(Succeed())
+			Expect(rawQuery).To(Equal(expectedQuery))
+		},
+		Entry("start", func(c bosh.Client) error {
+			return c.StartInstances("some-deployment", bosh.InstanceGroup("api"), bosh.InstanceStateOptions{})
+		}, "/deployments/some-deployment/jobs/api", "state=started"),
+		Entry("stop", func(c bosh.Client) error {
+			return c.StopInstances("some-deployment", bosh.InstanceGroup("api"), bosh.InstanceStateOptions{SkipDrain: true})
+		}, "/deployments/some-deployment/jobs/api", "skip_drain=true&state=stopped"),
+		Entry("hard stop", func(c bosh.Client) error {
+			return c.HardStopInstances("some-deployment", bosh.InstanceGroup("api"), bosh.InstanceStateOptions{})
+		}, "/deployments/some-deployment/jobs/api", "state=detached"),
+		Entry("recreate", func(c bosh.Client) error {
+			return c.RecreateInstances("some-deployment", bosh.InstanceGroup("api"), bosh.InstanceStateOptions{
+				SkipDrain:   true,
+				Fix:         true,
+				Canaries:    "1",
+				MaxInFlight: "50%",
+			})
+		}, "/deployments/some-deployment/jobs/api", "canaries=1&fix=true&max_in_flight=50%25&skip_drain=true&state=recreate"),
+		Entry("restart", func(c bosh.Client) error {
+			return c.RestartInstances("some-deployment", bosh.InstanceByID("api", "some-uuid"), bosh.InstanceStateOptions{})
+		}, "/deployments/some-deployment/jobs/api/some-uuid", "state=restart"),
+	)
+
+	Context("failure cases", func() {
+		It("errors on an unexpected status code with a body", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte("More Info"))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			err := client.StartInstances("some-deployment", bosh.AllInstances(), bosh.InstanceStateOptions{})
+			Expect(err).To(MatchError("unexpected response 400 Bad Request:\nMore Info"))
+		})
+
+		It("returns the task error when the state change fails", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/deployments/some-deployment/jobs/api":
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/1", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/1":
+					w.Write([]byte(`{"id": 1, "state": "cancelled"}`))
+				default:
+					Fail("could not match any URL endpoints")
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			err := client.RecreateInstances("some-deployment", bosh.InstanceGroup("api"), bosh.InstanceStateOptions{})
+			Expect(err).To(MatchError("bosh task was cancelled"))
+		})
+	})
+})
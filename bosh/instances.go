@@ -0,0 +1,137 @@
+package bosh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type Instance struct {
+	ID                 string            `json:"id"`
+	Index              int               `json:"index"`
+	Group              string            `json:"job_name"`
+	AZ                 string            `json:"az"`
+	Bootstrap          bool              `json:"bootstrap"`
+	State              string            `json:"state"`
+	ProcessState       string            `json:"job_state"`
+	VMCID              string            `json:"vm_cid"`
+	AgentID            string            `json:"agent_id"`
+	VMType             string            `json:"vm_type"`
+	DiskCIDs           []string          `json:"disk_cids"`
+	IPs                []string          `json:"ips"`
+	ExpectsVM          bool              `json:"expects_vm"`
+	ResurrectionPaused bool              `json:"resurrection_paused"`
+	Ignore             bool              `json:"ignore"`
+	Processes          []InstanceProcess `json:"processes"`
+	Vitals             InstanceVitals    `json:"vitals"`
+}
+
+type InstanceProcess struct {
+	Name   string `json:"name"`
+	State  string `json:"state"`
+	Uptime struct {
+		Secs int `json:"secs"`
+	} `json:"uptime"`
+	Mem struct {
+		KB      int     `json:"kb"`
+		Percent float64 `json:"percent"`
+	} `json:"mem"`
+	CPU struct {
+		Total float64 `json:"total"`
+	} `json:"cpu"`
+}
+
+type InstanceVitals struct {
+	CPU struct {
+		Sys  string `json:"sys"`
+		User string `json:"user"`
+		Wait string `json:"wait"`
+	} `json:"cpu"`
+	Mem    VitalsMemory          `json:"mem"`
+	Swap   VitalsMemory          `json:"swap"`
+	Load   []string              `json:"load"`
+	Disk   map[string]VitalsDisk `json:"disk"`
+	Uptime struct {
+		Secs int `json:"secs"`
+	} `json:"uptime"`
+}
+
+type VitalsMemory struct {
+	KB      string `json:"kb"`
+	Percent string `json:"percent"`
+}
+
+type VitalsDisk struct {
+	Percent      string `json:"percent"`
+	InodePercent string `json:"inode_percent"`
+}
+
+func (i Instance) HasVM() bool {
+	return i.VMCID != ""
+}
+
+func (i Instance) Process(name string) (InstanceProcess, bool) {
+	for _, process := range i.Processes {
+		if process.Name == name {
+			return process, true
+		}
+	}
+
+	return InstanceProcess{}, false
+}
+
+func (c Client) Instances(deployment string) ([]Instance, error) {
+	return c.InstancesContext(context.Background(), deployment)
+}
+
+func (c Client) InstancesContext(ctx context.Context, deployment string) ([]Instance, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/deployments/%s/instances?format=full", c.config.URL, deployment), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.makeRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := bodyReader(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusFound {
+		return nil, newDirectorError(response, body)
+	}
+
+	taskId, err := c.checkTaskStatus(ctx, response.Header.Get("Location"))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.taskOutputFrom(ctx, taskId, "result", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var instances []Instance
+	for _, line := range bytes.Split(result, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var instance Instance
+		err = json.Unmarshal(line, &instance)
+		if err != nil {
+			return nil, err
+		}
+
+		instances = append(instances, instance)
+	}
+
+	return instances, nil
+}
@@ -0,0 +1,138 @@
+package bosh_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Instances", func() {
+	It("returns every instance of the deployment with processes and vitals", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(username).To(Equal("some-username"))
+			Expect(password).To(Equal("some-password"))
+
+			switch r.URL.Path {
+			case "/deployments/some-deployment/instances":
+				Expect(r.Method).To(Equal("GET"))
+				Expect(r.URL.RawQuery).To(Equal("format=full"))
+
+				w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/4", r.Host))
+				w.WriteHeader(http.StatusFound)
+			case "/tasks/4":
+				w.Write([]byte(`{"id": 4, "state": "done"}`))
+			case "/tasks/4/output":
+				Expect(r.URL.RawQuery).To(Equal("type=result"))
+
+				w.Write([]byte(`{"id": "some-uuid", "index": 0, "job_name": "api", "az": "z1", "bootstrap": true, "state": "started", "job_state": "running", "vm_cid": "some-vm-cid", "agent_id": "some-agent-id", "vm_type": "small", "disk_cids": ["some-disk-cid"], "ips": ["10.0.0.5"], "expects_vm": true, "resurrection_paused": true, "ignore": false, "processes": [{"name": "api", "state": "running", "uptime": {"secs": 120}, "mem": {"kb": 2048, "percent": 1.5}, "cpu": {"total": 0.3}}], "vitals": {"cpu": {"sys": "1.0", "user": "2.0", "wait": "0.1"}, "mem": {"kb": "100000", "percent": "10"}, "swap": {"kb": "0", "percent": "0"}, "load": ["0.01", "0.02", "0.03"], "disk": {"system": {"percent": "40", "inode_percent": "30"}, "persistent": {"percent": "5", "inode_percent": "1"}}, "uptime": {"secs": 3600}}}
+{"id": "some-other-uuid", "index": 1, "job_name": "api", "az": "z2", "bootstrap": false, "state": "detached", "job_state": null, "vm_cid": null, "agent_id": null, "vm_type": "small", "disk_cids": [], "ips": [], "expects_vm": false, "resurrection_paused": false, "ignore": true, "processes": [], "vitals": null}
+`))
+			default:
+				Fail("could not match any URL endpoints")
+			}
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			Username:            "some-username",
+			Password:            "some-password",
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		instances, err := client.Instances("some-deployment")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instances).To(HaveLen(2))
+
+		instance := instances[0]
+		Expect(instance.ID).To(Equal("some-uuid"))
+		Expect(instance.Index).To(Equal(0))
+		Expect(instance.Group).To(Equal("api"))
+		Expect(instance.AZ).To(Equal("z1"))
+		Expect(instance.Bootstrap).To(BeTrue())
+		Expect(instance.State).To(Equal("started"))
+		Expect(instance.ProcessState).To(Equal("running"))
+		Expect(instance.VMCID).To(Equal("some-vm-cid"))
+		Expect(instance.AgentID).To(Equal("some-agent-id"))
+		Expect(instance.VMType).To(Equal("small"))
+		Expect(instance.DiskCIDs).To(Equal([]string{"some-disk-cid"}))
+		Expect(instance.IPs).To(Equal([]string{"10.0.0.5"}))
+		Expect(instance.ResurrectionPaused).To(BeTrue())
+		Expect(instance.Ignore).To(BeFalse())
+		Expect(instance.HasVM()).To(BeTrue())
+
+		process, ok := instance.Process("api")
+		Expect(ok).To(BeTrue())
+		Expect(process.State).To(Equal("running"))
+		Expect(process.Uptime.Secs).To(Equal(120))
+		Expect(process.Mem.KB).To(Equal(2048))
+		Expect(process.Mem.Percent).To(Equal(1.5))
+		Expect(process.CPU.Total).To(Equal(0.3))
+
+		_, ok = instance.Process("missing")
+		Expect(ok).To(BeFalse())
+
+		Expect(instance.Vitals.CPU.User).To(Equal("2.0"))
+		Expect(instance.Vitals.Mem).To(Equal(bosh.VitalsMemory{KB: "100000", Percent: "10"}))
+		Expect(instance.Vitals.Swap).To(Equal(bosh.VitalsMemory{KB: "0", Percent: "0"}))
+		Expect(instance.Vitals.Load).To(Equal([]string{"0.01", "0.02", "0.03"}))
+		Expect(instance.Vitals.Disk).To(Equal(map[string]bosh.VitalsDisk{
+			"system":     {Percent: "40", InodePercent: "30"},
+			"persistent": {Percent: "5", InodePercent: "1"},
+		}))
+		Expect(instance.Vitals.Uptime.Secs).To(Equal(3600))
+
+		Expect(instances[1].ID).To(Equal("some-other-uuid"))
+		Expect(instances[1].State).To(Equal("detached"))
+		Expect(instances[1].Ignore).To(BeTrue())
+		Expect(instances[1].HasVM()).To(BeFalse())
+	})
+
+	Context("failure cases", func() {
+		It("errors on an unexpected status code with a body", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"code": 70000, "description": "Deployment 'some-deployment' doesn't exist"}`))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			_, err := client.Instances("some-deployment")
+			Expect(errors.Is(err, bosh.ErrDeploymentNotFound)).To(BeTrue())
+		})
+
+		It("errors on malformed result JSON", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/deployments/some-deployment/instances":
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/4", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/4":
+					w.Write([]byte(`{"id": 4, "state": "done"}`))
+				case "/tasks/4/output":
+					w.Write([]byte(`%%%%`))
+				default:
+					Fail("could not match any URL endpoints")
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			_, err := client.Instances("some-deployment")
+			Expect(err).To(MatchError(ContainSubstring("invalid character")))
+		})
+	})
+})
@@ -0,0 +1,167 @@
+package bosh
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type LogsOptions struct {
+	Type    string
+	Filters []string
+}
+
+func (o LogsOptions) query() url.Values {
+	query := url.Values{}
+
+	logType := o.Type
+	if logType == "" {
+		logType = "job"
+	}
+	query.Set("type", logType)
+
+	if len(o.Filters) > 0 {
+		query.Set("filters", strings.Join(o.Filters, ","))
+	}
+
+	return query
+}
+
+func (s InstanceSelector) logsPath() string {
+	group, id := "*", "*"
+	if s.Group != "" {
+		group = url.PathEscape(s.Group)
+	}
+
+	if s.ID != "" {
+		id = url.PathEscape(s.ID)
+	}
+
+	return fmt.Sprintf("%s/%s", group, id)
+}
+
+func (c Client) Logs(deployment string, selector InstanceSelector, options LogsOptions) (io.ReadCloser, error) {
+	return c.LogsContext(context.Background(), deployment, selector, options)
+}
+
+func (c Client) LogsContext(ctx context.Context, deployment string, selector InstanceSelector, options LogsOptions) (io.ReadCloser, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/deployments/%s/jobs/%s/logs?%s", c.config.URL, deployment, selector.logsPath(), options.query().Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.makeRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := bodyReader(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusFound {
+		return nil, newDirectorError(response, body)
+	}
+
+	taskId, err := c.checkTaskStatus(ctx, response.Header.Get("Location"))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.taskOutputFrom(ctx, taskId, "result", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	blobstoreID := strings.TrimSpace(string(result))
+	if blobstoreID == "" {
+		return nil, fmt.Errorf("bosh task %d did not return a logs blobstore id", taskId)
+	}
+
+	return c.ResourceContext(ctx, blobstoreID)
+}
+
+func (c Client) SaveLogs(deployment string, selector InstanceSelector, options LogsOptions, dir string) error {
+	return c.SaveLogsContext(context.Background(), deployment, selector, options, dir)
+}
+
+func (c Client) SaveLogsContext(ctx context.Context, deployment string, selector InstanceSelector, options LogsOptions, dir string) error {
+	logs, err := c.LogsContext(ctx, deployment, selector, options)
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+
+	return ExtractTarball(logs, dir)
+}
+
+func ExtractTarball(tarball io.Reader, dir string) error {
+	gzipReader, err := gzip.NewReader(tarball)
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+
+	err = os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(dir, header.Name)
+		if path != filepath.Clean(dir) && !strings.HasPrefix(path, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("tarball entry %q is outside of %s", header.Name, dir)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(path, os.ModePerm)
+			if err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			err = os.MkdirAll(filepath.Dir(path), os.ModePerm)
+			if err != nil {
+				return err
+			}
+
+			err = writeTarballEntry(path, tarReader, os.FileMode(header.Mode).Perm())
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeTarballEntry(path string, contents io.Reader, mode os.FileMode) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode|0200)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(file, contents)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
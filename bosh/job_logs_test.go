@@ -0,0 +1,279 @@
+package bosh_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("job logs", func() {
+	Describe("Logs", func() {
+		It("fetches the logs tarball for the selected instances", func() {
+			logsTarball := buildTarball(map[string]string{"./api/api.stdout.log": "some-stdout"})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				username, password, ok := r.BasicAuth()
+				Expect(ok).To(BeTrue())
+				Expect(username).To(Equal("some-username"))
+				Expect(password).To(Equal("some-password"))
+
+				switch r.URL.Path {
+				case "/deployments/some-deployment/jobs/api/some-uuid/logs":
+					Expect(r.Method).To(Equal("GET"))
+					Expect(r.URL.RawQuery).To(Equal("type=job"))
+
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/7", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/7":
+					w.Write([]byte(`{"id": 7, "state": "done"}`))
+				case "/tasks/7/output":
+					Expect(r.URL.RawQuery).To(Equal("type=result"))
+					w.Write([]byte("some-blobstore-id\n"))
+				case "/resources/some-blobstore-id":
+					w.Write(logsTarball)
+				default:
+					Fail(fmt.Sprintf("unhandled request to %s", r.URL.Path))
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				Username:            "some-username",
+				Password:            "some-password",
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			logs, err := client.Logs("some-deployment", bosh.InstanceByID("api", "some-uuid"), bosh.LogsOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			defer logs.Close()
+
+			contents, err := ioutil.ReadAll(logs)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contents).To(Equal(logsTarball))
+		})
+
+		It("addresses the whole deployment or an instance group", func() {
+			var paths []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				username, password, ok := r.BasicAuth()
+				Expect(ok).To(BeTrue())
+				Expect(username).To(Equal("some-username"))
+				Expect(password).To(Equal("some-password"))
+
+				switch r.URL.Path {
+				case "/deployments/some-deployment/jobs/*/*/logs", "/deployments/some-deployment/jobs/api/*/logs":
+					Expect(r.Method).To(Equal("GET"))
+					paths = append(paths, r.URL.EscapedPath())
+
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/7", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/7":
+					w.Write([]byte(`{"id": 7, "state": "done"}`))
+				case "/tasks/7/output":
+					Expect(r.URL.RawQuery).To(Equal("type=result"))
+					w.Write([]byte("some-blobstore-id\n"))
+				case "/resources/some-blobstore-id":
+					w.Write(buildTarball(map[string]string{}))
+				default:
+					Fail(fmt.Sprintf("unhandled request to %s", r.URL.Path))
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				Username:            "some-username",
+				Password:            "some-password",
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			logs, err := client.Logs("some-deployment", bosh.AllInstances(), bosh.LogsOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			logs.Close()
+
+			logs, err = client.Logs("some-deployment", bosh.InstanceGroup("api"), bosh.LogsOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			logs.Close()
+
+			Expect(paths).To(Equal([]string{
+				"/deployments/some-deployment/jobs/*/*/logs",
+				"/deployments/some-deployment/jobs/api/*/logs",
+			}))
+		})
+
+		It("sends the log type and filters", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				username, password, ok := r.BasicAuth()
+				Expect(ok).To(BeTrue())
+				Expect(username).To(Equal("some-username"))
+				Expect(password).To(Equal("some-password"))
+
+				switch r.URL.Path {
+				case "/deployments/some-deployment/jobs/*/*/logs":
+					Expect(r.Method).To(Equal("GET"))
+					Expect(r.URL.RawQuery).To(Equal("filters=api%2Cworker&type=agent"))
+
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/7", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/7":
+					w.Write([]byte(`{"id": 7, "state": "done"}`))
+				case "/tasks/7/output":
+					Expect(r.URL.RawQuery).To(Equal("type=result"))
+					w.Write([]byte("some-blobstore-id\n"))
+				case "/resources/some-blobstore-id":
+					w.Write(buildTarball(map[string]string{}))
+				default:
+					Fail(fmt.Sprintf("unhandled request to %s", r.URL.Path))
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				Username:            "some-username",
+				Password:            "some-password",
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			logs, err := client.Logs("some-deployment", bosh.AllInstances(), bosh.LogsOptions{
+				Type:    "agent",
+				Filters: []string{"api", "worker"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			logs.Close()
+		})
+
+		Context("failure cases", func() {
+			It("returns an error on an unexpected status code", func() {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte("More Info"))
+				}))
+
+				client := bosh.NewClient(bosh.Config{
+					URL: server.URL,
+				})
+
+				_, err := client.Logs("some-deployment", bosh.AllInstances(), bosh.LogsOptions{})
+				Expect(err).To(MatchError("unexpected response 400 Bad Request:\nMore Info"))
+			})
+
+			It("returns an error when the task has no blobstore id", func() {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch r.URL.Path {
+					case "/deployments/some-deployment/jobs/*/*/logs":
+						w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/7", r.Host))
+						w.WriteHeader(http.StatusFound)
+					case "/tasks/7":
+						w.Write([]byte(`{"id": 7, "state": "done"}`))
+					case "/tasks/7/output":
+						w.Write([]byte(""))
+					default:
+						Fail("could not match any URL endpoints")
+					}
+				}))
+
+				client := bosh.NewClient(bosh.Config{
+					URL:                 server.URL,
+					TaskPollingInterval: time.Nanosecond,
+				})
+
+				_, err := client.Logs("some-deployment", bosh.AllInstances(), bosh.LogsOptions{})
+				Expect(err).To(MatchError("bosh task 7 did not return a logs blobstore id"))
+			})
+		})
+	})
+
+	Describe("SaveLogs", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "logs")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("extracts the logs into the directory", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				username, password, ok := r.BasicAuth()
+				Expect(ok).To(BeTrue())
+				Expect(username).To(Equal("some-username"))
+				Expect(password).To(Equal("some-password"))
+
+				switch r.URL.Path {
+				case "/deployments/some-deployment/jobs/api/*/logs":
+					Expect(r.Method).To(Equal("GET"))
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/7", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/7":
+					w.Write([]byte(`{"id": 7, "state": "done"}`))
+				case "/tasks/7/output":
+					Expect(r.URL.RawQuery).To(Equal("type=result"))
+					w.Write([]byte("some-blobstore-id\n"))
+				case "/resources/some-blobstore-id":
+					w.Write(buildTarball(map[string]string{
+						"./api/api.stdout.log": "some-stdout",
+						"./api/api.stderr.log": "some-stderr",
+					}))
+				default:
+					Fail(fmt.Sprintf("unhandled request to %s", r.URL.Path))
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				Username:            "some-username",
+				Password:            "some-password",
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			err := client.SaveLogs("some-deployment", bosh.InstanceGroup("api"), bosh.LogsOptions{}, filepath.Join(dir, "api"))
+			Expect(err).NotTo(HaveOccurred())
+
+			contents, err := ioutil.ReadFile(filepath.Join(dir, "api", "api", "api.stdout.log"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("some-stdout"))
+
+			contents, err = ioutil.ReadFile(filepath.Join(dir, "api", "api", "api.stderr.log"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("some-stderr"))
+		})
+	})
+
+	Describe("ExtractTarball", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "extract")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("refuses entries outside of the directory", func() {
+			err := bosh.ExtractTarball(bytes.NewReader(buildTarball(map[string]string{
+				"../escaped.log": "some-contents",
+			})), dir)
+			Expect(err).To(MatchError(ContainSubstring(`tarball entry "../escaped.log" is outside of`)))
+		})
+
+		It("returns an error when the stream is not gzipped", func() {
+			err := bosh.ExtractTarball(bytes.NewReader([]byte("not a tarball")), dir)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -2,6 +2,7 @@ package bosh
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,7 +15,11 @@ type Lock struct {
 }
 
 func (c Client) Locks() ([]Lock, error) {
-	request, err := http.NewRequest("GET", fmt.Sprintf("%s/locks", c.config.URL), bytes.NewBuffer([]byte{}))
+	return c.LocksContext(context.Background())
+}
+
+func (c Client) LocksContext(ctx context.Context) ([]Lock, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/locks", c.config.URL), bytes.NewBuffer([]byte{}))
 	if err != nil {
 		return nil, err
 	}
@@ -25,7 +30,13 @@ func (c Client) Locks() ([]Lock, error) {
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected response %d %s", response.StatusCode, http.StatusText(response.StatusCode))
+		body, err := bodyReader(response.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer response.Body.Close()
+
+		return nil, newDirectorError(response, body)
 	}
 
 	var locks []Lock
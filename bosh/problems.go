@@ -0,0 +1,167 @@
+package bosh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+type ProblemType string
+
+const (
+	ProblemUnresponsiveAgent ProblemType = "unresponsive_agent"
+	ProblemMissingVM         ProblemType = "missing_vm"
+	ProblemMissingDisk       ProblemType = "missing_disk"
+	ProblemInactiveDisk      ProblemType = "inactive_disk"
+	ProblemMountInfoMismatch ProblemType = "mount_info_mismatch"
+)
+
+const (
+	ResolutionIgnore                = "ignore"
+	ResolutionRebootVM              = "reboot_vm"
+	ResolutionRecreateVM            = "recreate_vm"
+	ResolutionRecreateVMWithoutWait = "recreate_vm_without_wait"
+	ResolutionDeleteVMReference     = "delete_vm_reference"
+	ResolutionDeleteDiskReference   = "delete_disk_reference"
+	ResolutionReattachDisk          = "reattach_disk"
+	ResolutionReattachDiskAndReboot = "reattach_disk_and_reboot"
+	ResolutionActivateDisk          = "activate_disk"
+	ResolutionDeleteDisk            = "delete_disk"
+)
+
+type Problem struct {
+	ID            int                    `json:"id"`
+	Type          ProblemType            `json:"type"`
+	Description   string                 `json:"description"`
+	InstanceGroup string                 `json:"instance_group"`
+	InstanceID    string                 `json:"instance_id"`
+	Data          map[string]interface{} `json:"data"`
+	Resolutions   []ProblemResolution    `json:"resolutions"`
+}
+
+type ProblemResolution struct {
+	Name string `json:"name"`
+	Plan string `json:"plan"`
+}
+
+func (p Problem) HasResolution(name string) bool {
+	for _, resolution := range p.Resolutions {
+		if resolution.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c Client) ScanForProblems(deployment string) error {
+	return c.ScanForProblemsContext(context.Background(), deployment)
+}
+
+func (c Client) ScanForProblemsContext(ctx context.Context, deployment string) error {
+	request, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/deployments/%s/scans", c.config.URL, deployment), bytes.NewBuffer([]byte("{}")))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	return c.doProblemsTaskRequest(ctx, request)
+}
+
+func (c Client) Problems(deployment string) ([]Problem, error) {
+	return c.ProblemsContext(context.Background(), deployment)
+}
+
+func (c Client) ProblemsContext(ctx context.Context, deployment string) ([]Problem, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/deployments/%s/problems", c.config.URL, deployment), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.makeRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := bodyReader(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, newDirectorError(response, body)
+	}
+
+	problems := []Problem{}
+	err = json.Unmarshal(body, &problems)
+	if err != nil {
+		return nil, err
+	}
+
+	return problems, nil
+}
+
+func (c Client) ScanAndListProblems(deployment string) ([]Problem, error) {
+	return c.ScanAndListProblemsContext(context.Background(), deployment)
+}
+
+func (c Client) ScanAndListProblemsContext(ctx context.Context, deployment string) ([]Problem, error) {
+	err := c.ScanForProblemsContext(ctx, deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.ProblemsContext(ctx, deployment)
+}
+
+func (c Client) ResolveProblems(deployment string, resolutions map[int]string) error {
+	return c.ResolveProblemsContext(context.Background(), deployment, resolutions)
+}
+
+func (c Client) ResolveProblemsContext(ctx context.Context, deployment string, resolutions map[int]string) error {
+	payload := map[string]map[string]string{
+		"resolutions": {},
+	}
+	for id, resolution := range resolutions {
+		payload["resolutions"][strconv.Itoa(id)] = resolution
+	}
+
+	requestBody, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/deployments/%s/problems", c.config.URL, deployment), bytes.NewBuffer(requestBody))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	return c.doProblemsTaskRequest(ctx, request)
+}
+
+func (c Client) doProblemsTaskRequest(ctx context.Context, request *http.Request) error {
+	response, err := c.makeRequest(request)
+	if err != nil {
+		return err
+	}
+
+	body, err := bodyReader(response.Body)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusFound {
+		return newDirectorError(response, body)
+	}
+
+	_, err = c.checkTaskStatus(ctx, response.Header.Get("Location"))
+	return err
+}
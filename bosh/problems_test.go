@@ -0,0 +1,197 @@
+package bosh_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("problems", func() {
+	It("scans the deployment and lists typed problems", func() {
+		var calls []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(username).To(Equal("some-username"))
+			Expect(password).To(Equal("some-password"))
+
+			calls = append(calls, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+
+			switch fmt.Sprintf("%s %s", r.Method, r.URL.Path) {
+			case "POST /deployments/some-deployment/scans":
+				Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+				w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/9", r.Host))
+				w.WriteHeader(http.StatusFound)
+			case "GET /tasks/9":
+				w.Write([]byte(`{"id": 9, "state": "done"}`))
+			case "GET /deployments/some-deployment/problems":
+				w.Write([]byte(`[
+					{
+						"id": 4,
+						"type": "unresponsive_agent",
+						"description": "VM for 'api/some-uuid (0)' with cloud ID 'vm-1' is not responding.",
+						"instance_group": "api",
+						"instance_id": "some-uuid",
+						"data": {"vm_cid": "vm-1"},
+						"resolutions": [
+							{"name": "ignore", "plan": "Skip for now"},
+							{"name": "reboot_vm", "plan": "Reboot VM"},
+							{"name": "recreate_vm", "plan": "Recreate VM and wait for processes to start"}
+						]
+					},
+					{
+						"id": 5,
+						"type": "missing_disk",
+						"description": "Disk 'disk-1' is missing",
+						"data": {},
+						"resolutions": [
+							{"name": "ignore", "plan": "Skip for now"},
+							{"name": "delete_disk_reference", "plan": "Delete disk reference"}
+						]
+					}
+				]`))
+			default:
+				Fail(fmt.Sprintf("unhandled request to %s %s", r.Method, r.URL.Path))
+			}
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			Username:            "some-username",
+			Password:            "some-password",
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		problems, err := client.ScanAndListProblems("some-deployment")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(calls).To(Equal([]string{
+			"POST /deployments/some-deployment/scans",
+			"GET /tasks/9",
+			"GET /deployments/some-deployment/problems",
+		}))
+
+		Expect(problems).To(HaveLen(2))
+		Expect(problems[0]).To(Equal(bosh.Problem{
+			ID:            4,
+			Type:          bosh.ProblemUnresponsiveAgent,
+			Description:   "VM for 'api/some-uuid (0)' with cloud ID 'vm-1' is not responding.",
+			InstanceGroup: "api",
+			InstanceID:    "some-uuid",
+			Data:          map[string]interface{}{"vm_cid": "vm-1"},
+			Resolutions: []bosh.ProblemResolution{
+				{Name: bosh.ResolutionIgnore, Plan: "Skip for now"},
+				{Name: bosh.ResolutionRebootVM, Plan: "Reboot VM"},
+				{Name: bosh.ResolutionRecreateVM, Plan: "Recreate VM and wait for processes to start"},
+			},
+		}))
+		Expect(problems[0].HasResolution(bosh.ResolutionRecreateVM)).To(BeTrue())
+		Expect(problems[1].Type).To(Equal(bosh.ProblemMissingDisk))
+		Expect(problems[1].HasResolution(bosh.ResolutionRecreateVM)).To(BeFalse())
+	})
+
+	It("submits the chosen resolution for each problem", func() {
+		var calls []string
+		var resolutions map[string]map[string]string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(username).To(Equal("some-username"))
+			Expect(password).To(Equal("some-password"))
+
+			calls = append(calls, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+
+			switch fmt.Sprintf("%s %s", r.Method, r.URL.Path) {
+			case "PUT /deployments/some-deployment/problems":
+				Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+				Expect(json.NewDecoder(r.Body).Decode(&resolutions)).To(Succeed())
+				w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/9", r.Host))
+				w.WriteHeader(http.StatusFound)
+			case "GET /tasks/9":
+				w.Write([]byte(`{"id": 9, "state": "done"}`))
+			default:
+				Fail(fmt.Sprintf("unhandled request to %s %s", r.Method, r.URL.Path))
+			}
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			Username:            "some-username",
+			Password:            "some-password",
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		err := client.ResolveProblems("some-deployment", map[int]string{
+			4: bosh.ResolutionRecreateVM,
+			5: bosh.ResolutionIgnore,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(calls).To(Equal([]string{
+			"PUT /deployments/some-deployment/problems",
+			"GET /tasks/9",
+		}))
+		Expect(resolutions).To(Equal(map[string]map[string]string{
+			"resolutions": {
+				"4": "recreate_vm",
+				"5": "ignore",
+			},
+		}))
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when the scan cannot be started", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte("More Info"))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			_, err := client.ScanAndListProblems("some-deployment")
+			Expect(err).To(MatchError("unexpected response 400 Bad Request:\nMore Info"))
+		})
+
+		It("returns an error when the problems cannot be parsed", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("%%%"))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			_, err := client.Problems("some-deployment")
+			Expect(err).To(MatchError(ContainSubstring("invalid character")))
+		})
+
+		It("returns an error when the resolution task fails", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/deployments/some-deployment/problems":
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/9", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/9":
+					w.Write([]byte(`{"id": 9, "state": "cancelled"}`))
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			err := client.ResolveProblems("some-deployment", map[int]string{4: bosh.ResolutionRebootVM})
+			Expect(err).To(MatchError("bosh task was cancelled"))
+		})
+	})
+})
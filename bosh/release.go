@@ -1,6 +1,7 @@
 package bosh
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -16,7 +17,11 @@ func NewRelease() Release {
 }
 
 func (c Client) Release(name string) (Release, error) {
-	request, err := http.NewRequest("GET", fmt.Sprintf("%s/releases/%s", c.config.URL, name), nil)
+	return c.ReleaseContext(context.Background(), name)
+}
+
+func (c Client) ReleaseContext(ctx context.Context, name string) (Release, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/releases/%s", c.config.URL, name), nil)
 	if err != nil {
 		return Release{}, err
 	}
@@ -26,10 +31,6 @@ func (c Client) Release(name string) (Release, error) {
 		return Release{}, err
 	}
 
-	if response.StatusCode == http.StatusNotFound {
-		return Release{}, fmt.Errorf("release %s could not be found", name)
-	}
-
 	if response.StatusCode != http.StatusOK {
 		body, err := bodyReader(response.Body)
 		if err != nil {
@@ -37,7 +38,13 @@ func (c Client) Release(name string) (Release, error) {
 		}
 		defer response.Body.Close()
 
-		return Release{}, fmt.Errorf("unexpected response %d %s:\n%s", response.StatusCode, http.StatusText(response.StatusCode), body)
+		directorErr := newDirectorError(response, body)
+		if response.StatusCode == http.StatusNotFound {
+			directorErr.message = fmt.Sprintf("release %s could not be found", name)
+			directorErr.kind = ErrReleaseNotFound
+		}
+
+		return Release{}, directorErr
 	}
 
 	release := NewRelease()
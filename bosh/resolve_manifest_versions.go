@@ -1,6 +1,10 @@
 package bosh
 
-import yaml "gopkg.in/yaml.v2"
+import (
+	"context"
+
+	yaml "gopkg.in/yaml.v2"
+)
 
 type manifest struct {
 	Name        interface{} `yaml:"name"`
@@ -36,6 +40,10 @@ type manifest struct {
 }
 
 func (c Client) ResolveManifestVersionsV2(manifestYAML []byte) ([]byte, error) {
+	return c.ResolveManifestVersionsV2Context(context.Background(), manifestYAML)
+}
+
+func (c Client) ResolveManifestVersionsV2Context(ctx context.Context, manifestYAML []byte) ([]byte, error) {
 	m := manifest{}
 	err := yaml.Unmarshal(manifestYAML, &m)
 	if err != nil {
@@ -44,7 +52,7 @@ func (c Client) ResolveManifestVersionsV2(manifestYAML []byte) ([]byte, error) {
 
 	for i, r := range m.Releases {
 		if r.Version == "latest" {
-			release, err := c.Release(r.Name)
+			release, err := c.ReleaseContext(ctx, r.Name)
 			if err != nil {
 				return nil, err
 			}
@@ -55,7 +63,7 @@ func (c Client) ResolveManifestVersionsV2(manifestYAML []byte) ([]byte, error) {
 
 	for i, stemcell := range m.Stemcells {
 		if stemcell.Version == "latest" {
-			stemcell, err := c.StemcellByOS(stemcell.OS)
+			stemcell, err := c.StemcellByOSContext(ctx, stemcell.OS)
 			if err != nil {
 				return nil, err
 			}
@@ -1,13 +1,18 @@
 package bosh
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 )
 
 func (c Client) Resource(resourceId string) (io.ReadCloser, error) {
-	request, err := http.NewRequest("GET", fmt.Sprintf("%s/resources/%s", c.config.URL, resourceId), nil)
+	return c.ResourceContext(context.Background(), resourceId)
+}
+
+func (c Client) ResourceContext(ctx context.Context, resourceId string) (io.ReadCloser, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/resources/%s", c.config.URL, resourceId), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -24,7 +29,7 @@ func (c Client) Resource(resourceId string) (io.ReadCloser, error) {
 		}
 		defer response.Body.Close()
 
-		return nil, fmt.Errorf("unexpected response %s:\n%s", response.Status, body)
+		return nil, newDirectorError(response, body)
 	}
 
 	return response.Body, nil
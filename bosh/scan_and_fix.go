@@ -2,6 +2,7 @@ package bosh
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -10,7 +11,11 @@ import (
 )
 
 func (c Client) ScanAndFix(deploymentName, jobName string, jobIndices []int) error {
-	return c.doScanAndFixRequest(deploymentName, map[string]interface{}{
+	return c.ScanAndFixContext(context.Background(), deploymentName, jobName, jobIndices)
+}
+
+func (c Client) ScanAndFixContext(ctx context.Context, deploymentName, jobName string, jobIndices []int) error {
+	return c.doScanAndFixRequest(ctx, deploymentName, map[string]interface{}{
 		"jobs": map[string][]int{
 			jobName: jobIndices,
 		},
@@ -18,12 +23,19 @@ func (c Client) ScanAndFix(deploymentName, jobName string, jobIndices []int) err
 }
 
 func (c Client) ScanAndFixAll(manifestYAML []byte) error {
+	return c.ScanAndFixAllContext(context.Background(), manifestYAML)
+}
+
+func (c Client) ScanAndFixAllContext(ctx context.Context, manifestYAML []byte) error {
+	type instanceGroup struct {
+		Name      string
+		Instances int
+	}
+
 	var manifest struct {
-		Name string
-		Jobs []struct {
-			Name      string
-			Instances int
-		}
+		Name           string
+		Jobs           []instanceGroup
+		InstanceGroups []instanceGroup `yaml:"instance_groups"`
 	}
 	err := yaml.Unmarshal(manifestYAML, &manifest)
 	if err != nil {
@@ -31,7 +43,7 @@ func (c Client) ScanAndFixAll(manifestYAML []byte) error {
 	}
 
 	jobs := make(map[string][]int)
-	for _, j := range manifest.Jobs {
+	for _, j := range append(manifest.Jobs, manifest.InstanceGroups...) {
 		if j.Instances > 0 {
 			var indices []int
 			for i := 0; i < j.Instances; i++ {
@@ -41,17 +53,41 @@ func (c Client) ScanAndFixAll(manifestYAML []byte) error {
 		}
 	}
 
-	return c.doScanAndFixRequest(manifest.Name, map[string]interface{}{
+	return c.doScanAndFixRequest(ctx, manifest.Name, map[string]interface{}{
+		"jobs": jobs,
+	})
+}
+
+func (c Client) ScanAndFixDeployment(deploymentName string) error {
+	return c.ScanAndFixDeploymentContext(context.Background(), deploymentName)
+}
+
+func (c Client) ScanAndFixDeploymentContext(ctx context.Context, deploymentName string) error {
+	instances, err := c.InstancesContext(ctx, deploymentName)
+	if err != nil {
+		return err
+	}
+
+	jobs := make(map[string][]int)
+	for _, instance := range instances {
+		if !instance.ExpectsVM {
+			continue
+		}
+
+		jobs[instance.Group] = append(jobs[instance.Group], instance.Index)
+	}
+
+	return c.doScanAndFixRequest(ctx, deploymentName, map[string]interface{}{
 		"jobs": jobs,
 	})
 }
 
-func (c Client) doScanAndFixRequest(deploymentName string, payload map[string]interface{}) error {
+func (c Client) doScanAndFixRequest(ctx context.Context, deploymentName string, payload map[string]interface{}) error {
 	requestBody, err := json.Marshal(payload)
 	if err != nil {
 		return err
 	}
-	request, err := http.NewRequest("PUT", fmt.Sprintf("%s/deployments/%s/scan_and_fix", c.config.URL, deploymentName), bytes.NewBuffer(requestBody))
+	request, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/deployments/%s/scan_and_fix", c.config.URL, deploymentName), bytes.NewBuffer(requestBody))
 	if err != nil {
 		return err
 	}
@@ -70,10 +106,10 @@ func (c Client) doScanAndFixRequest(deploymentName string, payload map[string]in
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusFound {
-		return fmt.Errorf("unexpected response %d %s:\n%s", response.StatusCode, http.StatusText(response.StatusCode), responseBody)
+		return newDirectorError(response, responseBody)
 	}
 
-	_, err = c.checkTaskStatus(response.Header.Get("Location"))
+	_, err = c.checkTaskStatus(ctx, response.Header.Get("Location"))
 	if err != nil {
 		return err
 	}
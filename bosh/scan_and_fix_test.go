@@ -142,6 +142,52 @@ jobs:
 			Expect(callCount).To(Equal(4))
 		})
 
+		It("scans and fixes all instances of a manifest with instance groups", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/deployments/some-deployment-name/scan_and_fix":
+					body, err := ioutil.ReadAll(r.Body)
+					Expect(err).NotTo(HaveOccurred())
+					defer r.Body.Close()
+
+					Expect(string(body)).To(MatchJSON(`{
+						"jobs":{
+							"consul": [0,1,2],
+							"api": [0]
+						}
+					}`))
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/1", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/1":
+					w.Write([]byte(`{"state": "done"}`))
+				default:
+					Fail("unexpected route")
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			yaml := `---
+name: some-deployment-name
+instance_groups:
+  - name: consul
+    instances: 3
+    jobs:
+    - name: consul_agent
+      release: consul
+  - name: errand
+    instances: 0
+  - name: api
+    instances: 1
+`
+
+			err := client.ScanAndFixAll([]byte(yaml))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
 		Context("failure cases", func() {
 			It("errors on malformed yaml", func() {
 				client := bosh.NewClient(bosh.Config{
@@ -156,6 +202,65 @@ jobs:
 		})
 	})
 
+	Describe("ScanAndFixDeployment", func() {
+		It("scans and fixes the live instances of a deployment", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/deployments/some-deployment-name/instances":
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/2", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/2":
+					w.Write([]byte(`{"id": 2, "state": "done"}`))
+				case "/tasks/2/output":
+					w.Write([]byte(`{"job_name":"consul","index":0,"expects_vm":true}
+{"job_name":"consul","index":2,"expects_vm":true}
+{"job_name":"smoke-tests","index":0,"expects_vm":false}
+{"job_name":"api","index":4,"expects_vm":true}
+`))
+				case "/deployments/some-deployment-name/scan_and_fix":
+					body, err := ioutil.ReadAll(r.Body)
+					Expect(err).NotTo(HaveOccurred())
+					defer r.Body.Close()
+
+					Expect(string(body)).To(MatchJSON(`{
+						"jobs":{
+							"consul": [0,2],
+							"api": [4]
+						}
+					}`))
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/1", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/1":
+					w.Write([]byte(`{"state": "done"}`))
+				default:
+					Fail("unexpected route")
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			err := client.ScanAndFixDeployment("some-deployment-name")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns an error when the instances cannot be listed", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte("More Info"))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			err := client.ScanAndFixDeployment("some-deployment-name")
+			Expect(err).To(MatchError("unexpected response 400 Bad Request:\nMore Info"))
+		})
+	})
+
 	Context("failure cases", func() {
 		It("errors when the bosh URL is malformed", func() {
 			client := bosh.NewClient(bosh.Config{
@@ -1,6 +1,7 @@
 package bosh
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -25,8 +26,8 @@ func NewStemcell() Stemcell {
 	return Stemcell{}
 }
 
-func (c Client) getStemcells(name string) ([]stemcell, error) {
-	request, err := http.NewRequest("GET", fmt.Sprintf("%s/stemcells", c.config.URL), nil)
+func (c Client) getStemcells(ctx context.Context, name string) ([]stemcell, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/stemcells", c.config.URL), nil)
 	if err != nil {
 		return []stemcell{}, err
 	}
@@ -36,10 +37,6 @@ func (c Client) getStemcells(name string) ([]stemcell, error) {
 		return []stemcell{}, err
 	}
 
-	if response.StatusCode == http.StatusNotFound {
-		return []stemcell{}, fmt.Errorf("stemcell %s could not be found", name)
-	}
-
 	if response.StatusCode != http.StatusOK {
 		body, err := bodyReader(response.Body)
 		if err != nil {
@@ -47,7 +44,13 @@ func (c Client) getStemcells(name string) ([]stemcell, error) {
 		}
 		defer response.Body.Close()
 
-		return []stemcell{}, fmt.Errorf("unexpected response %d %s:\n%s", response.StatusCode, http.StatusText(response.StatusCode), body)
+		directorErr := newDirectorError(response, body)
+		if response.StatusCode == http.StatusNotFound {
+			directorErr.message = fmt.Sprintf("stemcell %s could not be found", name)
+			directorErr.kind = ErrStemcellNotFound
+		}
+
+		return []stemcell{}, directorErr
 	}
 
 	var stemcells []stemcell
@@ -61,7 +64,11 @@ func (c Client) getStemcells(name string) ([]stemcell, error) {
 }
 
 func (c Client) StemcellByName(name string) (Stemcell, error) {
-	stemcells, err := c.getStemcells(name)
+	return c.StemcellByNameContext(context.Background(), name)
+}
+
+func (c Client) StemcellByNameContext(ctx context.Context, name string) (Stemcell, error) {
+	stemcells, err := c.getStemcells(ctx, name)
 	if err != nil {
 		return Stemcell{}, err
 	}
@@ -79,7 +86,11 @@ func (c Client) StemcellByName(name string) (Stemcell, error) {
 }
 
 func (c Client) StemcellByOS(os string) (Stemcell, error) {
-	stemcells, err := c.getStemcells(os)
+	return c.StemcellByOSContext(context.Background(), os)
+}
+
+func (c Client) StemcellByOSContext(ctx context.Context, os string) (Stemcell, error) {
+	stemcells, err := c.getStemcells(ctx, os)
 	if err != nil {
 		return Stemcell{}, err
 	}
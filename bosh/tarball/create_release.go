@@ -0,0 +1,890 @@
+package tarball
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+type CreateReleaseOptions struct {
+	Name        string
+	Version     string
+	Final       bool
+	BlobsDir    string
+	TarballPath string
+}
+
+type CreatedRelease struct {
+	Name        string
+	Version     string
+	TarballPath string
+	Release     Release
+}
+
+type releaseFile struct {
+	path         string
+	relativePath string
+	excludeMode  bool
+}
+
+type builtArtifact struct {
+	name         string
+	fingerprint  string
+	sha1         string
+	contents     []byte
+	dependencies []string
+	packages     []string
+}
+
+type buildIndex struct {
+	Builds        map[string]map[string]string `yaml:"builds"`
+	FormatVersion string                       `yaml:"format-version"`
+}
+
+type blobEntry struct {
+	Size int64  `yaml:"size"`
+	SHA  string `yaml:"sha"`
+}
+
+// CreateRelease builds a release tarball from a release directory the way
+// `bosh create-release --tarball` does, reading blobs from a local directory
+// instead of a blobstore.
+func CreateRelease(releaseDir string, options CreateReleaseOptions) (CreatedRelease, error) {
+	name, err := releaseName(releaseDir, options)
+	if err != nil {
+		return CreatedRelease{}, err
+	}
+
+	blobsDir := options.BlobsDir
+	if blobsDir == "" {
+		blobsDir = filepath.Join(releaseDir, "blobs")
+	}
+
+	blobs := map[string]blobEntry{}
+	err = readYAML(filepath.Join(releaseDir, "config", "blobs.yml"), &blobs)
+	if err != nil && !os.IsNotExist(err) {
+		return CreatedRelease{}, fmt.Errorf("failed to parse config/blobs.yml: %s", err)
+	}
+
+	packages, err := buildPackages(releaseDir, blobsDir, blobs)
+	if err != nil {
+		return CreatedRelease{}, err
+	}
+
+	jobs, err := buildJobs(releaseDir)
+	if err != nil {
+		return CreatedRelease{}, err
+	}
+
+	license, err := buildLicense(releaseDir)
+	if err != nil {
+		return CreatedRelease{}, err
+	}
+
+	releasesDir := "dev_releases"
+	if options.Final {
+		releasesDir = "releases"
+	}
+
+	// Final build indexes need blobstore ids, which an offline build cannot
+	// produce, so builds are only ever recorded in the dev index.
+	for kind, artifacts := range map[string][]builtArtifact{"jobs": jobs, "packages": packages} {
+		for _, artifact := range artifacts {
+			err = recordBuild(filepath.Join(releaseDir, ".dev_builds", kind, artifact.name, "index.yml"), artifact.fingerprint, map[string]string{
+				"version": artifact.fingerprint,
+				"sha1":    artifact.sha1,
+			})
+			if err != nil {
+				return CreatedRelease{}, err
+			}
+		}
+	}
+
+	version := options.Version
+	if version == "" {
+		version, err = nextReleaseVersion(releaseDir, name, options.Final)
+		if err != nil {
+			return CreatedRelease{}, err
+		}
+	}
+
+	manifest := Release{
+		Name:               name,
+		Version:            version,
+		CommitHash:         commitHash(releaseDir),
+		UncommittedChanges: false,
+	}
+
+	for _, job := range jobs {
+		manifest.Jobs = append(manifest.Jobs, Job{
+			Name:        job.name,
+			Version:     job.fingerprint,
+			Fingerprint: job.fingerprint,
+			SHA1:        job.sha1,
+			Packages:    job.packages,
+		})
+	}
+
+	for _, pkg := range packages {
+		manifest.Packages = append(manifest.Packages, Package{
+			Name:         pkg.name,
+			Version:      pkg.fingerprint,
+			Fingerprint:  pkg.fingerprint,
+			SHA1:         pkg.sha1,
+			Dependencies: pkg.dependencies,
+		})
+	}
+
+	if license != nil {
+		manifest.License = &License{
+			Version:     license.fingerprint,
+			Fingerprint: license.fingerprint,
+			SHA1:        license.sha1,
+		}
+	}
+
+	manifestYAML, err := yaml.Marshal(manifest)
+	if err != nil {
+		return CreatedRelease{}, err
+	}
+
+	entries := []tarEntry{{name: "./release.MF", contents: manifestYAML}}
+	for _, job := range jobs {
+		entries = append(entries, tarEntry{name: fmt.Sprintf("./jobs/%s.tgz", job.name), contents: job.contents})
+	}
+	for _, pkg := range packages {
+		entries = append(entries, tarEntry{name: fmt.Sprintf("./packages/%s.tgz", pkg.name), contents: pkg.contents})
+	}
+	if license != nil {
+		entries = append(entries, tarEntry{name: "./license.tgz", contents: license.contents})
+	}
+
+	tgz, err := writeTarball(entries)
+	if err != nil {
+		return CreatedRelease{}, err
+	}
+
+	tarballPath := options.TarballPath
+	if tarballPath == "" {
+		tarballPath = filepath.Join(releaseDir, releasesDir, name, fmt.Sprintf("%s-%s.tgz", name, version))
+	}
+
+	err = os.MkdirAll(filepath.Dir(tarballPath), os.ModePerm)
+	if err != nil {
+		return CreatedRelease{}, err
+	}
+
+	err = ioutil.WriteFile(tarballPath, tgz, 0644)
+	if err != nil {
+		return CreatedRelease{}, err
+	}
+
+	indexDir := filepath.Join(releaseDir, releasesDir, name)
+	err = os.MkdirAll(indexDir, os.ModePerm)
+	if err != nil {
+		return CreatedRelease{}, err
+	}
+
+	err = ioutil.WriteFile(filepath.Join(indexDir, fmt.Sprintf("%s-%s.yml", name, version)), manifestYAML, 0644)
+	if err != nil {
+		return CreatedRelease{}, err
+	}
+
+	buildID, err := newBuildID()
+	if err != nil {
+		return CreatedRelease{}, err
+	}
+
+	err = recordBuild(filepath.Join(indexDir, "index.yml"), buildID, map[string]string{"version": version})
+	if err != nil {
+		return CreatedRelease{}, err
+	}
+
+	release, err := ReadReleaseFrom(bytes.NewReader(tgz))
+	if err != nil {
+		return CreatedRelease{}, err
+	}
+
+	return CreatedRelease{
+		Name:        name,
+		Version:     version,
+		TarballPath: tarballPath,
+		Release:     release,
+	}, nil
+}
+
+// fingerprint computes a job, package or license fingerprint the same way
+// the bosh CLI does: a sha1 over "v2", each file's path, sha1 and mode in
+// path order, and any additional chunks such as package dependencies.
+func fingerprint(files []releaseFile, additionalChunks []string) (string, error) {
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].relativePath < files[j].relativePath
+	})
+
+	chunks := []string{"v2"}
+	for _, file := range files {
+		info, err := os.Stat(file.path)
+		if err != nil {
+			return "", err
+		}
+
+		chunk := file.relativePath
+		if !info.IsDir() {
+			contents, err := os.Open(file.path)
+			if err != nil {
+				return "", err
+			}
+
+			sum, err := sha1Of(contents)
+			contents.Close()
+			if err != nil {
+				return "", err
+			}
+			chunk += sum
+		}
+
+		if !file.excludeMode {
+			if info.Mode()&0111 != 0 {
+				chunk += "100755"
+			} else {
+				chunk += "100644"
+			}
+		}
+
+		chunks = append(chunks, chunk)
+	}
+
+	chunks = append(chunks, strings.Join(additionalChunks, ","))
+
+	return fmt.Sprintf("%x", sha1.Sum([]byte(strings.Join(chunks, "")))), nil
+}
+
+func buildPackages(releaseDir, blobsDir string, blobs map[string]blobEntry) ([]builtArtifact, error) {
+	packageDirs, err := subdirectories(filepath.Join(releaseDir, "packages"))
+	if err != nil {
+		return nil, err
+	}
+
+	srcDir := filepath.Join(releaseDir, "src")
+	srcFiles, err := listFiles(srcDir)
+	if err != nil {
+		return nil, err
+	}
+
+	blobFiles, err := listFiles(blobsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var packages []builtArtifact
+	for _, packageDir := range packageDirs {
+		var spec struct {
+			Name          string   `yaml:"name"`
+			Dependencies  []string `yaml:"dependencies"`
+			Files         []string `yaml:"files"`
+			ExcludedFiles []string `yaml:"excluded_files"`
+		}
+		err = readYAML(filepath.Join(packageDir, "spec"), &spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read package spec %s: %s", filepath.Join(packageDir, "spec"), err)
+		}
+
+		if spec.Name == "" {
+			spec.Name = filepath.Base(packageDir)
+		}
+
+		files := []releaseFile{{
+			path:         filepath.Join(packageDir, "packaging"),
+			relativePath: "packaging",
+			excludeMode:  true,
+		}}
+
+		if _, err := os.Stat(files[0].path); err != nil {
+			return nil, fmt.Errorf("package %s has no packaging script", spec.Name)
+		}
+
+		seen := map[string]bool{}
+		for _, pattern := range spec.Files {
+			matched := false
+			for _, candidates := range []struct {
+				dir   string
+				files []string
+			}{{srcDir, srcFiles}, {blobsDir, blobFiles}} {
+				for _, relativePath := range candidates.files {
+					if seen[relativePath] || !globMatch(pattern, relativePath) || globMatchAny(spec.ExcludedFiles, relativePath) {
+						continue
+					}
+
+					if candidates.dir == blobsDir {
+						err = verifyBlob(filepath.Join(blobsDir, relativePath), relativePath, blobs)
+						if err != nil {
+							return nil, err
+						}
+					}
+
+					seen[relativePath] = true
+					matched = true
+					files = append(files, releaseFile{
+						path:         filepath.Join(candidates.dir, relativePath),
+						relativePath: relativePath,
+					})
+				}
+			}
+
+			if !matched {
+				return nil, fmt.Errorf("package %s has no files matching %q in src or blobs", spec.Name, pattern)
+			}
+		}
+
+		dependencies := append([]string{}, spec.Dependencies...)
+		sort.Strings(dependencies)
+
+		prePackaging := releaseFile{
+			path:         filepath.Join(packageDir, "pre_packaging"),
+			relativePath: "pre_packaging",
+			excludeMode:  true,
+		}
+
+		var artifact builtArtifact
+		if _, err = os.Stat(prePackaging.path); err == nil {
+			artifact, err = buildPrePackagedArtifact(spec.Name, files, prePackaging, dependencies)
+		} else {
+			artifact, err = buildArtifact(spec.Name, files, dependencies)
+		}
+		if err != nil {
+			return nil, err
+		}
+		artifact.dependencies = spec.Dependencies
+
+		packages = append(packages, artifact)
+	}
+
+	return packages, nil
+}
+
+func buildJobs(releaseDir string) ([]builtArtifact, error) {
+	jobDirs, err := subdirectories(filepath.Join(releaseDir, "jobs"))
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []builtArtifact
+	for _, jobDir := range jobDirs {
+		var spec struct {
+			Name      string            `yaml:"name"`
+			Templates map[string]string `yaml:"templates"`
+			Packages  []string          `yaml:"packages"`
+		}
+		err = readYAML(filepath.Join(jobDir, "spec"), &spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read job spec %s: %s", filepath.Join(jobDir, "spec"), err)
+		}
+
+		if spec.Name == "" {
+			spec.Name = filepath.Base(jobDir)
+		}
+
+		files := []releaseFile{
+			{path: filepath.Join(jobDir, "spec"), relativePath: "job.MF"},
+		}
+
+		monit := filepath.Join(jobDir, "monit")
+		if _, err := os.Stat(monit); err == nil {
+			files = append(files, releaseFile{path: monit, relativePath: "monit"})
+		}
+
+		for template := range spec.Templates {
+			templatePath := filepath.Join(jobDir, "templates", template)
+			if _, err := os.Stat(templatePath); err != nil {
+				return nil, fmt.Errorf("job %s is missing template %s", spec.Name, template)
+			}
+
+			files = append(files, releaseFile{
+				path:         templatePath,
+				relativePath: filepath.ToSlash(filepath.Join("templates", template)),
+			})
+		}
+
+		artifact, err := buildArtifact(spec.Name, files, nil)
+		if err != nil {
+			return nil, err
+		}
+		artifact.packages = spec.Packages
+
+		jobs = append(jobs, artifact)
+	}
+
+	return jobs, nil
+}
+
+func buildLicense(releaseDir string) (*builtArtifact, error) {
+	var files []releaseFile
+	for _, name := range []string{"LICENSE", "NOTICE"} {
+		path := filepath.Join(releaseDir, name)
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, releaseFile{path: path, relativePath: name})
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	artifact, err := buildArtifact("license", files, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &artifact, nil
+}
+
+func buildArtifact(name string, files []releaseFile, additionalChunks []string) (builtArtifact, error) {
+	fingerprint, err := fingerprint(append([]releaseFile{}, files...), additionalChunks)
+	if err != nil {
+		return builtArtifact{}, err
+	}
+
+	var entries []tarEntry
+	for _, file := range files {
+		contents, err := ioutil.ReadFile(file.path)
+		if err != nil {
+			return builtArtifact{}, err
+		}
+
+		info, err := os.Stat(file.path)
+		if err != nil {
+			return builtArtifact{}, err
+		}
+
+		entries = append(entries, tarEntry{
+			name:     "./" + file.relativePath,
+			contents: contents,
+			mode:     int64(info.Mode().Perm()),
+		})
+	}
+
+	return newBuiltArtifact(name, fingerprint, entries)
+}
+
+// buildPrePackagedArtifact mirrors the bosh cli for packages with a
+// pre_packaging script: the script is part of the fingerprint, runs against
+// a staged copy of the package files with BUILD_DIR set, and the archive is
+// whatever it leaves behind.
+func buildPrePackagedArtifact(name string, files []releaseFile, prePackaging releaseFile, additionalChunks []string) (builtArtifact, error) {
+	fingerprint, err := fingerprint(append([]releaseFile{prePackaging}, files...), additionalChunks)
+	if err != nil {
+		return builtArtifact{}, err
+	}
+
+	buildDir, err := ioutil.TempDir("", "pre-packaging")
+	if err != nil {
+		return builtArtifact{}, err
+	}
+	defer os.RemoveAll(buildDir)
+
+	for _, file := range files {
+		err = copyFile(file.path, filepath.Join(buildDir, filepath.FromSlash(file.relativePath)))
+		if err != nil {
+			return builtArtifact{}, err
+		}
+	}
+
+	command := exec.Command("bash", "-x", prePackaging.path)
+	command.Dir = buildDir
+	command.Env = append(os.Environ(), fmt.Sprintf("BUILD_DIR=%s", buildDir))
+
+	output, err := command.CombinedOutput()
+	if err != nil {
+		return builtArtifact{}, fmt.Errorf("pre_packaging for package %s failed: %s\n%s", name, err, output)
+	}
+
+	stagedFiles, err := listFiles(buildDir)
+	if err != nil {
+		return builtArtifact{}, err
+	}
+
+	var entries []tarEntry
+	for _, relativePath := range stagedFiles {
+		path := filepath.Join(buildDir, filepath.FromSlash(relativePath))
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return builtArtifact{}, err
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return builtArtifact{}, err
+		}
+
+		entries = append(entries, tarEntry{
+			name:     "./" + relativePath,
+			contents: contents,
+			mode:     int64(info.Mode().Perm()),
+		})
+	}
+
+	return newBuiltArtifact(name, fingerprint, entries)
+}
+
+func newBuiltArtifact(name, fingerprint string, entries []tarEntry) (builtArtifact, error) {
+	contents, err := writeTarball(entries)
+	if err != nil {
+		return builtArtifact{}, err
+	}
+
+	return builtArtifact{
+		name:        name,
+		fingerprint: fingerprint,
+		sha1:        fmt.Sprintf("%x", sha1.Sum(contents)),
+		contents:    contents,
+	}, nil
+}
+
+func copyFile(source, destination string) error {
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+
+	contents, err := ioutil.ReadFile(source)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(destination), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(destination, contents, info.Mode().Perm())
+}
+
+type tarEntry struct {
+	name     string
+	contents []byte
+	mode     int64
+}
+
+// writeTarball leaves modification times unset so identical inputs produce
+// identical tarballs.
+func writeTarball(entries []tarEntry) ([]byte, error) {
+	buffer := bytes.NewBuffer([]byte{})
+	gzipWriter := gzip.NewWriter(buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, entry := range entries {
+		mode := entry.mode
+		if mode == 0 {
+			mode = 0644
+		}
+
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name:     entry.name,
+			Mode:     mode,
+			Size:     int64(len(entry.contents)),
+			Typeflag: tar.TypeReg,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tarWriter.Write(entry.contents)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := tarWriter.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = gzipWriter.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func verifyBlob(path, relativePath string, blobs map[string]blobEntry) error {
+	blob, ok := blobs[relativePath]
+	if !ok || blob.SHA == "" {
+		return nil
+	}
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	for _, digest := range strings.Split(blob.SHA, ";") {
+		algorithm, expected := "sha1", strings.TrimSpace(digest)
+		if i := strings.Index(expected, ":"); i >= 0 {
+			algorithm, expected = expected[:i], expected[i+1:]
+		}
+
+		var actual string
+		switch algorithm {
+		case "sha1":
+			actual = fmt.Sprintf("%x", sha1.Sum(contents))
+		case "sha256":
+			actual = fmt.Sprintf("%x", sha256.Sum256(contents))
+		default:
+			continue
+		}
+
+		if actual != expected {
+			return fmt.Errorf("blob %s has %s %s, expected %s", relativePath, algorithm, actual, expected)
+		}
+	}
+
+	return nil
+}
+
+func releaseName(releaseDir string, options CreateReleaseOptions) (string, error) {
+	if options.Name != "" {
+		return options.Name, nil
+	}
+
+	var finalConfig struct {
+		Name      string `yaml:"name"`
+		FinalName string `yaml:"final_name"`
+	}
+	err := readYAML(filepath.Join(releaseDir, "config", "final.yml"), &finalConfig)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to parse config/final.yml: %s", err)
+	}
+
+	if !options.Final {
+		var devConfig struct {
+			DevName string `yaml:"dev_name"`
+		}
+		err = readYAML(filepath.Join(releaseDir, "config", "dev.yml"), &devConfig)
+		if err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to parse config/dev.yml: %s", err)
+		}
+
+		if devConfig.DevName != "" {
+			return devConfig.DevName, nil
+		}
+	}
+
+	for _, name := range []string{finalConfig.Name, finalConfig.FinalName} {
+		if name != "" {
+			return name, nil
+		}
+	}
+
+	return "", errors.New("a release name is required: set it in config/final.yml or pass one")
+}
+
+func nextReleaseVersion(releaseDir, name string, final bool) (string, error) {
+	var finalIndex buildIndex
+	err := readYAML(filepath.Join(releaseDir, "releases", name, "index.yml"), &finalIndex)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	latestFinal := 0
+	for _, build := range finalIndex.Builds {
+		version, err := strconv.Atoi(strings.SplitN(build["version"], ".", 2)[0])
+		if err == nil && version > latestFinal {
+			latestFinal = version
+		}
+	}
+
+	if final {
+		return strconv.Itoa(latestFinal + 1), nil
+	}
+
+	var devIndex buildIndex
+	err = readYAML(filepath.Join(releaseDir, "dev_releases", name, "index.yml"), &devIndex)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	prefix := fmt.Sprintf("%d+dev.", latestFinal)
+	latestDev := 0
+	for _, build := range devIndex.Builds {
+		if !strings.HasPrefix(build["version"], prefix) {
+			continue
+		}
+
+		version, err := strconv.Atoi(strings.TrimPrefix(build["version"], prefix))
+		if err == nil && version > latestDev {
+			latestDev = version
+		}
+	}
+
+	return fmt.Sprintf("%s%d", prefix, latestDev+1), nil
+}
+
+func recordBuild(indexPath, key string, build map[string]string) error {
+	index := buildIndex{}
+	err := readYAML(indexPath, &index)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if index.Builds == nil {
+		index.Builds = map[string]map[string]string{}
+	}
+	index.Builds[key] = build
+	index.FormatVersion = "2"
+
+	contents, err := yaml.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(indexPath), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(indexPath, contents, 0644)
+}
+
+func commitHash(releaseDir string) string {
+	head, err := ioutil.ReadFile(filepath.Join(releaseDir, ".git", "HEAD"))
+	if err != nil {
+		return "non-git"
+	}
+
+	ref := strings.TrimSpace(string(head))
+	if strings.HasPrefix(ref, "ref: ") {
+		commit, err := ioutil.ReadFile(filepath.Join(releaseDir, ".git", filepath.FromSlash(strings.TrimPrefix(ref, "ref: "))))
+		if err != nil {
+			return "non-git"
+		}
+		ref = strings.TrimSpace(string(commit))
+	}
+
+	if len(ref) > 7 {
+		ref = ref[:7]
+	}
+
+	return ref
+}
+
+func newBuildID() (string, error) {
+	id := make([]byte, 16)
+	_, err := io.ReadFull(rand.Reader, id)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:]), nil
+}
+
+func readYAML(path string, out interface{}) error {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return yaml.Unmarshal(contents, out)
+}
+
+func subdirectories(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, info := range infos {
+		if info.IsDir() {
+			dirs = append(dirs, filepath.Join(dir, info.Name()))
+		}
+	}
+
+	return dirs, nil
+}
+
+func listFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, filepath.ToSlash(relativePath))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+func globMatchAny(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if globMatch(pattern, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// globMatch supports the patterns package specs use: "*" and "?" within a
+// path segment and "**" across segments.
+func globMatch(pattern, path string) bool {
+	var expression strings.Builder
+	expression.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expression.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expression.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expression.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expression.WriteString("[^/]")
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		}
+	}
+	expression.WriteString("$")
+
+	matched, err := regexp.MatchString(expression.String(), path)
+	return err == nil && matched
+}
@@ -0,0 +1,314 @@
+package tarball_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh/tarball"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CreateRelease", func() {
+	var releaseDir string
+
+	var writeFile = func(path, contents string) {
+		path = filepath.Join(releaseDir, path)
+		Expect(os.MkdirAll(filepath.Dir(path), os.ModePerm)).To(Succeed())
+		Expect(ioutil.WriteFile(path, []byte(contents), 0644)).To(Succeed())
+	}
+
+	var entriesOf = func(tgz []byte) []string {
+		gzipReader, err := gzip.NewReader(bytes.NewReader(tgz))
+		Expect(err).NotTo(HaveOccurred())
+
+		var entries []string
+		tarReader := tar.NewReader(gzipReader)
+		for {
+			header, err := tarReader.Next()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).NotTo(HaveOccurred())
+			entries = append(entries, header.Name)
+		}
+
+		sort.Strings(entries)
+		return entries
+	}
+
+	var entryOf = func(tgz []byte, name string) []byte {
+		gzipReader, err := gzip.NewReader(bytes.NewReader(tgz))
+		Expect(err).NotTo(HaveOccurred())
+
+		tarReader := tar.NewReader(gzipReader)
+		for {
+			header, err := tarReader.Next()
+			Expect(err).NotTo(HaveOccurred())
+
+			if header.Name == name {
+				contents, err := ioutil.ReadAll(tarReader)
+				Expect(err).NotTo(HaveOccurred())
+				return contents
+			}
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+		releaseDir, err = ioutil.TempDir("", "release-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		writeFile("config/final.yml", "---\nname: fixture\n")
+		writeFile("config/blobs.yml", fmt.Sprintf("golang/go.tgz:\n  size: 7\n  object_id: some-object\n  sha: sha256:%x\n", sha256.Sum256([]byte("go-blob"))))
+		writeFile("blobs/golang/go.tgz", "go-blob")
+		writeFile("LICENSE", "some license")
+
+		writeFile("src/server/main.go", "package main")
+		writeFile("src/server/sub/util.go", "package sub")
+		writeFile("src/server/main_test.go", "package main_test")
+
+		writeFile("packages/golang/spec", "---\nname: golang\nfiles:\n- golang/*.tgz\n")
+		writeFile("packages/golang/packaging", "tar xzf golang/go.tgz")
+		writeFile("packages/server/spec", "---\nname: server\ndependencies: [golang]\nfiles:\n- server/**/*.go\nexcluded_files:\n- server/**/*_test.go\n")
+		writeFile("packages/server/packaging", "go build ./server")
+
+		writeFile("jobs/server/spec", "---\nname: server\ntemplates:\n  ctl.erb: bin/ctl\npackages: [server]\nproperties:\n  port:\n    default: 8080\n")
+		writeFile("jobs/server/monit", "check process server")
+		writeFile("jobs/server/templates/ctl.erb", "#!/bin/bash")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(releaseDir)
+	})
+
+	It("creates a dev release tarball", func() {
+		created, err := tarball.CreateRelease(releaseDir, tarball.CreateReleaseOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(created.Name).To(Equal("fixture"))
+		Expect(created.Version).To(Equal("0+dev.1"))
+		Expect(created.TarballPath).To(Equal(filepath.Join(releaseDir, "dev_releases", "fixture", "fixture-0+dev.1.tgz")))
+		Expect(filepath.Join(releaseDir, "dev_releases", "fixture", "fixture-0+dev.1.yml")).To(BeAnExistingFile())
+
+		release, err := tarball.ReadRelease(created.TarballPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(release.Validate()).To(Succeed())
+		Expect(release.Name).To(Equal("fixture"))
+		Expect(release.Version).To(Equal("0+dev.1"))
+		Expect(release.CommitHash).To(Equal("non-git"))
+		Expect(release.License.Text).To(Equal("some license"))
+
+		job, ok := release.Job("server")
+		Expect(ok).To(BeTrue())
+		Expect(job.Packages).To(Equal([]string{"server"}))
+		Expect(job.Spec.Templates).To(Equal(map[string]string{"ctl.erb": "bin/ctl"}))
+
+		server, ok := release.Package("server")
+		Expect(ok).To(BeTrue())
+		Expect(server.Dependencies).To(Equal([]string{"golang"}))
+
+		contents, err := ioutil.ReadFile(created.TarballPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(entriesOf(contents)).To(Equal([]string{
+			"./jobs/server.tgz",
+			"./license.tgz",
+			"./packages/golang.tgz",
+			"./packages/server.tgz",
+			"./release.MF",
+		}))
+		Expect(entriesOf(entryOf(contents, "./packages/server.tgz"))).To(Equal([]string{
+			"./packaging",
+			"./server/main.go",
+			"./server/sub/util.go",
+		}))
+		Expect(entriesOf(entryOf(contents, "./packages/golang.tgz"))).To(Equal([]string{
+			"./golang/go.tgz",
+			"./packaging",
+		}))
+		Expect(entriesOf(entryOf(contents, "./jobs/server.tgz"))).To(Equal([]string{
+			"./job.MF",
+			"./monit",
+			"./templates/ctl.erb",
+		}))
+	})
+
+	It("computes fingerprints the way the bosh cli does", func() {
+		created, err := tarball.CreateRelease(releaseDir, tarball.CreateReleaseOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		golang, ok := created.Release.Package("golang")
+		Expect(ok).To(BeTrue())
+		Expect(golang.Fingerprint).To(Equal("9910aacf875931beda521e0d436981aaf80931aa"))
+		Expect(golang.Version).To(Equal(golang.Fingerprint))
+
+		server, ok := created.Release.Package("server")
+		Expect(ok).To(BeTrue())
+		Expect(server.Fingerprint).To(Equal("0d1152a2f55010337f3bedf35325c2e39b88b8fe"))
+
+		job, ok := created.Release.Job("server")
+		Expect(ok).To(BeTrue())
+		Expect(job.Fingerprint).To(Equal("075c533260ce368992dfd3578df1213e4a1e587a"))
+
+		index, err := ioutil.ReadFile(filepath.Join(releaseDir, ".dev_builds", "packages", "golang", "index.yml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(index)).To(ContainSubstring(golang.Fingerprint))
+	})
+
+	Context("when a package has several dependencies", func() {
+		BeforeEach(func() {
+			writeFile("packages/worker/spec", "---\nname: worker\ndependencies: [server, golang]\nfiles:\n- server/main.go\n")
+			writeFile("packages/worker/packaging", "go build ./worker")
+		})
+
+		It("fingerprints the sorted dependencies as a single comma separated chunk", func() {
+			created, err := tarball.CreateRelease(releaseDir, tarball.CreateReleaseOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			worker, ok := created.Release.Package("worker")
+			Expect(ok).To(BeTrue())
+			Expect(worker.Dependencies).To(Equal([]string{"server", "golang"}))
+			Expect(worker.Fingerprint).To(Equal("95ade9378df1958456100bf1f86e465b8fd73ef0"))
+		})
+	})
+
+	Context("when a package has a pre_packaging script", func() {
+		BeforeEach(func() {
+			writeFile("packages/server/pre_packaging", "rm ${BUILD_DIR}/server/sub/util.go\necho generated > ${BUILD_DIR}/server/generated.txt\n")
+		})
+
+		It("runs it against the package files and fingerprints it", func() {
+			created, err := tarball.CreateRelease(releaseDir, tarball.CreateReleaseOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			server, ok := created.Release.Package("server")
+			Expect(ok).To(BeTrue())
+			Expect(server.Fingerprint).To(Equal("56d82aae6522abe75e9e0435545ed7f0a5012aeb"))
+
+			contents, err := ioutil.ReadFile(created.TarballPath)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(entriesOf(entryOf(contents, "./packages/server.tgz"))).To(Equal([]string{
+				"./packaging",
+				"./server/generated.txt",
+				"./server/main.go",
+			}))
+		})
+
+		It("errors when the script fails", func() {
+			writeFile("packages/server/pre_packaging", "exit 1")
+
+			_, err := tarball.CreateRelease(releaseDir, tarball.CreateReleaseOptions{})
+			Expect(err).To(MatchError(ContainSubstring("pre_packaging for package server failed")))
+		})
+	})
+
+	It("only changes the fingerprints of changed artifacts", func() {
+		first, err := tarball.CreateRelease(releaseDir, tarball.CreateReleaseOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		writeFile("src/server/main.go", "package main // changed")
+
+		second, err := tarball.CreateRelease(releaseDir, tarball.CreateReleaseOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		firstGolang, _ := first.Release.Package("golang")
+		secondGolang, _ := second.Release.Package("golang")
+		Expect(secondGolang.Fingerprint).To(Equal(firstGolang.Fingerprint))
+		Expect(secondGolang.SHA1).To(Equal(firstGolang.SHA1))
+
+		firstServer, _ := first.Release.Package("server")
+		secondServer, _ := second.Release.Package("server")
+		Expect(secondServer.Fingerprint).NotTo(Equal(firstServer.Fingerprint))
+	})
+
+	It("increments dev and final versions", func() {
+		var versions []string
+		for _, final := range []bool{false, false, true, false, true} {
+			created, err := tarball.CreateRelease(releaseDir, tarball.CreateReleaseOptions{Final: final})
+			Expect(err).NotTo(HaveOccurred())
+			versions = append(versions, created.Version)
+		}
+
+		Expect(versions).To(Equal([]string{"0+dev.1", "0+dev.2", "1", "1+dev.1", "2"}))
+		Expect(filepath.Join(releaseDir, "releases", "fixture", "fixture-2.tgz")).To(BeAnExistingFile())
+		Expect(filepath.Join(releaseDir, ".final_builds")).NotTo(BeADirectory())
+		Expect(filepath.Join(releaseDir, ".dev_builds", "jobs", "server", "index.yml")).To(BeAnExistingFile())
+	})
+
+	It("uses the dev name for dev releases", func() {
+		writeFile("config/dev.yml", "---\ndev_name: fixture-dev\n")
+
+		created, err := tarball.CreateRelease(releaseDir, tarball.CreateReleaseOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created.Name).To(Equal("fixture-dev"))
+
+		created, err = tarball.CreateRelease(releaseDir, tarball.CreateReleaseOptions{Final: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created.Name).To(Equal("fixture"))
+	})
+
+	It("honours an explicit name, version, blobs directory and tarball path", func() {
+		blobsDir, err := ioutil.TempDir("", "blobs")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(blobsDir)
+
+		Expect(os.MkdirAll(filepath.Join(blobsDir, "golang"), os.ModePerm)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(blobsDir, "golang", "go.tgz"), []byte("go-blob"), 0644)).To(Succeed())
+		Expect(os.RemoveAll(filepath.Join(releaseDir, "blobs"))).To(Succeed())
+
+		tarballPath := filepath.Join(releaseDir, "out", "custom.tgz")
+		created, err := tarball.CreateRelease(releaseDir, tarball.CreateReleaseOptions{
+			Name:        "custom",
+			Version:     "9.9.9",
+			BlobsDir:    blobsDir,
+			TarballPath: tarballPath,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(created.Name).To(Equal("custom"))
+		Expect(created.Version).To(Equal("9.9.9"))
+		Expect(created.TarballPath).To(Equal(tarballPath))
+		Expect(tarballPath).To(BeAnExistingFile())
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when a blob does not match blobs.yml", func() {
+			writeFile("blobs/golang/go.tgz", "tampered")
+
+			_, err := tarball.CreateRelease(releaseDir, tarball.CreateReleaseOptions{})
+			Expect(err).To(MatchError(fmt.Sprintf("blob golang/go.tgz has sha256 %x, expected %x", sha256.Sum256([]byte("tampered")), sha256.Sum256([]byte("go-blob")))))
+		})
+
+		It("returns an error when a package file pattern matches nothing", func() {
+			Expect(os.RemoveAll(filepath.Join(releaseDir, "blobs"))).To(Succeed())
+
+			_, err := tarball.CreateRelease(releaseDir, tarball.CreateReleaseOptions{})
+			Expect(err).To(MatchError(`package golang has no files matching "golang/*.tgz" in src or blobs`))
+		})
+
+		It("returns an error when a job template is missing", func() {
+			Expect(os.Remove(filepath.Join(releaseDir, "jobs", "server", "templates", "ctl.erb"))).To(Succeed())
+
+			_, err := tarball.CreateRelease(releaseDir, tarball.CreateReleaseOptions{})
+			Expect(err).To(MatchError("job server is missing template ctl.erb"))
+		})
+
+		It("returns an error when the release has no name", func() {
+			Expect(os.Remove(filepath.Join(releaseDir, "config", "final.yml"))).To(Succeed())
+
+			_, err := tarball.CreateRelease(releaseDir, tarball.CreateReleaseOptions{})
+			Expect(err).To(MatchError("a release name is required: set it in config/final.yml or pass one"))
+		})
+	})
+})
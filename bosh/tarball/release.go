@@ -0,0 +1,309 @@
+package tarball
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+type Release struct {
+	Name               string            `yaml:"name"`
+	Version            string            `yaml:"version"`
+	CommitHash         string            `yaml:"commit_hash"`
+	UncommittedChanges bool              `yaml:"uncommitted_changes"`
+	Jobs               []Job             `yaml:"jobs"`
+	Packages           []Package         `yaml:"packages"`
+	CompiledPackages   []CompiledPackage `yaml:"compiled_packages,omitempty"`
+	License            *License          `yaml:"license,omitempty"`
+
+	SHA1     string `yaml:"-"`
+	Manifest []byte `yaml:"-"`
+
+	entries map[string]string
+}
+
+type Job struct {
+	Name        string   `yaml:"name"`
+	Version     string   `yaml:"version"`
+	Fingerprint string   `yaml:"fingerprint"`
+	SHA1        string   `yaml:"sha1"`
+	Packages    []string `yaml:"packages,omitempty"`
+	Spec        JobSpec  `yaml:"-"`
+}
+
+type JobSpec struct {
+	Name        string                 `yaml:"name"`
+	Description string                 `yaml:"description"`
+	Templates   map[string]string      `yaml:"templates"`
+	Packages    []string               `yaml:"packages"`
+	Properties  map[string]JobProperty `yaml:"properties"`
+	Consumes    []JobLink              `yaml:"consumes"`
+	Provides    []JobLink              `yaml:"provides"`
+}
+
+type JobProperty struct {
+	Description string      `yaml:"description"`
+	Default     interface{} `yaml:"default"`
+}
+
+type JobLink struct {
+	Name     string `yaml:"name"`
+	Type     string `yaml:"type"`
+	Optional bool   `yaml:"optional"`
+}
+
+type Package struct {
+	Name         string   `yaml:"name"`
+	Version      string   `yaml:"version"`
+	Fingerprint  string   `yaml:"fingerprint"`
+	SHA1         string   `yaml:"sha1"`
+	Dependencies []string `yaml:"dependencies"`
+}
+
+type CompiledPackage struct {
+	Name         string   `yaml:"name"`
+	Version      string   `yaml:"version"`
+	Fingerprint  string   `yaml:"fingerprint"`
+	SHA1         string   `yaml:"sha1"`
+	Stemcell     string   `yaml:"stemcell"`
+	Dependencies []string `yaml:"dependencies"`
+}
+
+type License struct {
+	Version     string `yaml:"version"`
+	Fingerprint string `yaml:"fingerprint"`
+	SHA1        string `yaml:"sha1"`
+	Text        string `yaml:"-"`
+	Notice      string `yaml:"-"`
+}
+
+type ManifestRelease struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	URL     string `yaml:"url,omitempty"`
+	SHA1    string `yaml:"sha1,omitempty"`
+}
+
+func ReadRelease(releasePath string) (Release, error) {
+	file, err := os.Open(releasePath)
+	if err != nil {
+		return Release{}, err
+	}
+	defer file.Close()
+
+	release, err := ReadReleaseFrom(file)
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to read release %s: %s", releasePath, err)
+	}
+
+	return release, nil
+}
+
+func ReadReleaseFrom(contents io.Reader) (Release, error) {
+	var manifest []byte
+	specs := map[string]JobSpec{}
+	entries := map[string]string{}
+	license := License{}
+
+	tarballSHA1, err := walk(contents, func(name string, contents io.Reader) error {
+		switch {
+		case name == "release.MF":
+			var err error
+			manifest, err = ioutil.ReadAll(contents)
+			return err
+
+		case strings.HasPrefix(name, "jobs/") && strings.HasSuffix(name, ".tgz"):
+			job, err := ioutil.ReadAll(contents)
+			if err != nil {
+				return err
+			}
+			entries[name] = fmt.Sprintf("%x", sha1.Sum(job))
+
+			spec, err := readJobSpec(job)
+			if err != nil {
+				return fmt.Errorf("failed to read job spec from %s: %s", name, err)
+			}
+			specs[strings.TrimSuffix(strings.TrimPrefix(name, "jobs/"), ".tgz")] = spec
+
+		case name == "license.tgz":
+			tgz, err := ioutil.ReadAll(contents)
+			if err != nil {
+				return err
+			}
+			entries[name] = fmt.Sprintf("%x", sha1.Sum(tgz))
+
+			license, err = readLicense(tgz)
+			if err != nil {
+				return fmt.Errorf("failed to read license: %s", err)
+			}
+
+		case strings.HasSuffix(name, ".tgz"):
+			sum, err := sha1Of(contents)
+			if err != nil {
+				return err
+			}
+			entries[name] = sum
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Release{}, err
+	}
+
+	if manifest == nil {
+		return Release{}, errors.New("release.MF not found")
+	}
+
+	var release Release
+	err = yaml.Unmarshal(manifest, &release)
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to parse release.MF: %s", err)
+	}
+
+	for i, job := range release.Jobs {
+		release.Jobs[i].Spec = specs[job.Name]
+	}
+
+	if release.License != nil {
+		release.License.Text = license.Text
+		release.License.Notice = license.Notice
+	}
+
+	release.SHA1 = tarballSHA1
+	release.Manifest = manifest
+	release.entries = entries
+
+	return release, nil
+}
+
+func (r Release) Job(name string) (Job, bool) {
+	for _, job := range r.Jobs {
+		if job.Name == name {
+			return job, true
+		}
+	}
+
+	return Job{}, false
+}
+
+func (r Release) Package(name string) (Package, bool) {
+	for _, pkg := range r.Packages {
+		if pkg.Name == name {
+			return pkg, true
+		}
+	}
+
+	return Package{}, false
+}
+
+func (r Release) ManifestEntry(url string) ManifestRelease {
+	entry := ManifestRelease{
+		Name:    r.Name,
+		Version: r.Version,
+	}
+
+	if url != "" {
+		entry.URL = url
+		entry.SHA1 = r.SHA1
+	}
+
+	return entry
+}
+
+// Validate checks that every job, package and compiled package listed in
+// release.MF is present in the tarball with the sha1 it declares.
+func (r Release) Validate() error {
+	var problems []string
+
+	check := func(entry, sha1 string) {
+		actual, ok := r.entries[entry]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%s is missing", entry))
+		case actual != sha1:
+			problems = append(problems, fmt.Sprintf("%s has sha1 %s, expected %s", entry, actual, sha1))
+		}
+	}
+
+	for _, job := range r.Jobs {
+		check(fmt.Sprintf("jobs/%s.tgz", job.Name), job.SHA1)
+	}
+
+	for _, pkg := range r.Packages {
+		check(fmt.Sprintf("packages/%s.tgz", pkg.Name), pkg.SHA1)
+	}
+
+	for _, pkg := range r.CompiledPackages {
+		check(fmt.Sprintf("compiled_packages/%s.tgz", pkg.Name), pkg.SHA1)
+	}
+
+	if r.License != nil {
+		check("license.tgz", r.License.SHA1)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("release %s/%s is invalid:\n  - %s", r.Name, r.Version, strings.Join(problems, "\n  - "))
+	}
+
+	return nil
+}
+
+func readJobSpec(job []byte) (JobSpec, error) {
+	var spec []byte
+	_, err := walk(bytes.NewReader(job), func(name string, contents io.Reader) error {
+		if name != "job.MF" {
+			return nil
+		}
+
+		var err error
+		spec, err = ioutil.ReadAll(contents)
+		return err
+	})
+	if err != nil {
+		return JobSpec{}, err
+	}
+
+	if spec == nil {
+		return JobSpec{}, errors.New("job.MF not found")
+	}
+
+	var jobSpec JobSpec
+	err = yaml.Unmarshal(spec, &jobSpec)
+	if err != nil {
+		return JobSpec{}, err
+	}
+
+	return jobSpec, nil
+}
+
+func readLicense(tgz []byte) (License, error) {
+	var license License
+	_, err := walk(bytes.NewReader(tgz), func(name string, contents io.Reader) error {
+		if name != "LICENSE" && name != "NOTICE" {
+			return nil
+		}
+
+		text, err := ioutil.ReadAll(contents)
+		if err != nil {
+			return err
+		}
+
+		if name == "LICENSE" {
+			license.Text = string(text)
+		} else {
+			license.Notice = string(text)
+		}
+
+		return nil
+	})
+
+	return license, err
+}
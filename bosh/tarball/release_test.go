@@ -0,0 +1,226 @@
+package tarball_test
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh/tarball"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Release", func() {
+	var (
+		jobTarball     []byte
+		licenseTarball []byte
+		files          map[string]string
+	)
+
+	var sha1Of = func(contents []byte) string {
+		return fmt.Sprintf("%x", sha1.Sum(contents))
+	}
+
+	BeforeEach(func() {
+		jobTarball = buildTarball(map[string]string{
+			"./job.MF": `---
+name: some-job
+description: does things
+templates:
+  ctl.erb: bin/ctl
+packages:
+- server
+properties:
+  port:
+    description: port to listen on
+    default: 8080
+consumes:
+- name: db
+  type: database
+  optional: true
+provides:
+- name: api
+  type: http
+`,
+			"./templates/ctl.erb": "#!/bin/bash",
+		})
+
+		licenseTarball = buildTarball(map[string]string{
+			"./LICENSE": "some license",
+			"./NOTICE":  "some notice",
+		})
+
+		files = map[string]string{
+			"./release.MF": fmt.Sprintf(`---
+name: some-release
+version: 1.2.3
+commit_hash: abc123
+uncommitted_changes: true
+jobs:
+- name: some-job
+  version: job-fingerprint
+  fingerprint: job-fingerprint
+  sha1: %s
+packages:
+- name: server
+  version: server-fingerprint
+  fingerprint: server-fingerprint
+  sha1: %s
+  dependencies: [golang]
+license:
+  version: license-fingerprint
+  fingerprint: license-fingerprint
+  sha1: %s
+`, sha1Of(jobTarball), sha1Of([]byte("server")), sha1Of(licenseTarball)),
+			"./jobs/some-job.tgz":   string(jobTarball),
+			"./packages/server.tgz": "server",
+			"./license.tgz":         string(licenseTarball),
+		}
+	})
+
+	Describe("ReadReleaseFrom", func() {
+		It("parses the release manifest, job specs and license", func() {
+			contents := buildTarball(files)
+
+			release, err := tarball.ReadReleaseFrom(bytes.NewReader(contents))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(release.Name).To(Equal("some-release"))
+			Expect(release.Version).To(Equal("1.2.3"))
+			Expect(release.CommitHash).To(Equal("abc123"))
+			Expect(release.UncommittedChanges).To(BeTrue())
+			Expect(release.SHA1).To(Equal(sha1Of(contents)))
+			Expect(string(release.Manifest)).To(Equal(files["./release.MF"]))
+
+			job, ok := release.Job("some-job")
+			Expect(ok).To(BeTrue())
+			Expect(job.Fingerprint).To(Equal("job-fingerprint"))
+			Expect(job.Spec).To(Equal(tarball.JobSpec{
+				Name:        "some-job",
+				Description: "does things",
+				Templates:   map[string]string{"ctl.erb": "bin/ctl"},
+				Packages:    []string{"server"},
+				Properties: map[string]tarball.JobProperty{
+					"port": {Description: "port to listen on", Default: 8080},
+				},
+				Consumes: []tarball.JobLink{{Name: "db", Type: "database", Optional: true}},
+				Provides: []tarball.JobLink{{Name: "api", Type: "http"}},
+			}))
+
+			pkg, ok := release.Package("server")
+			Expect(ok).To(BeTrue())
+			Expect(pkg).To(Equal(tarball.Package{
+				Name:         "server",
+				Version:      "server-fingerprint",
+				Fingerprint:  "server-fingerprint",
+				SHA1:         sha1Of([]byte("server")),
+				Dependencies: []string{"golang"},
+			}))
+
+			_, ok = release.Package("missing")
+			Expect(ok).To(BeFalse())
+
+			Expect(release.License.Fingerprint).To(Equal("license-fingerprint"))
+			Expect(release.License.Text).To(Equal("some license"))
+			Expect(release.License.Notice).To(Equal("some notice"))
+
+			Expect(release.Validate()).To(Succeed())
+		})
+
+		It("derives the manifest releases entry", func() {
+			release, err := tarball.ReadReleaseFrom(bytes.NewReader(buildTarball(files)))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(release.ManifestEntry("")).To(Equal(tarball.ManifestRelease{
+				Name:    "some-release",
+				Version: "1.2.3",
+			}))
+			Expect(release.ManifestEntry("https://example.com/release.tgz")).To(Equal(tarball.ManifestRelease{
+				Name:    "some-release",
+				Version: "1.2.3",
+				URL:     "https://example.com/release.tgz",
+				SHA1:    release.SHA1,
+			}))
+		})
+
+		It("reports missing and mismatched entries on validation", func() {
+			delete(files, "./license.tgz")
+			files["./packages/server.tgz"] = "tampered"
+
+			release, err := tarball.ReadReleaseFrom(bytes.NewReader(buildTarball(files)))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(release.Validate()).To(MatchError(fmt.Sprintf(`release some-release/1.2.3 is invalid:
+  - packages/server.tgz has sha1 %s, expected %s
+  - license.tgz is missing`, sha1Of([]byte("tampered")), sha1Of([]byte("server")))))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when release.MF is missing", func() {
+				delete(files, "./release.MF")
+
+				_, err := tarball.ReadReleaseFrom(bytes.NewReader(buildTarball(files)))
+				Expect(err).To(MatchError("release.MF not found"))
+			})
+
+			It("returns an error when release.MF is malformed", func() {
+				files["./release.MF"] = "%%%"
+
+				_, err := tarball.ReadReleaseFrom(bytes.NewReader(buildTarball(files)))
+				Expect(err).To(MatchError(ContainSubstring("failed to parse release.MF")))
+			})
+
+			It("returns an error when a job has no spec", func() {
+				files["./jobs/some-job.tgz"] = string(buildTarball(map[string]string{}))
+
+				_, err := tarball.ReadReleaseFrom(bytes.NewReader(buildTarball(files)))
+				Expect(err).To(MatchError("failed to read job spec from jobs/some-job.tgz: job.MF not found"))
+			})
+
+			It("returns an error when the stream is not a tarball", func() {
+				_, err := tarball.ReadReleaseFrom(bytes.NewReader([]byte("not a tarball")))
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("ReadRelease", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "release")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("reads the release from a path", func() {
+			releasePath := filepath.Join(dir, "release.tgz")
+			Expect(ioutil.WriteFile(releasePath, buildTarball(files), 0644)).To(Succeed())
+
+			release, err := tarball.ReadRelease(releasePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(release.Name).To(Equal("some-release"))
+		})
+
+		It("names the path in errors", func() {
+			releasePath := filepath.Join(dir, "release.tgz")
+			Expect(ioutil.WriteFile(releasePath, buildTarball(map[string]string{}), 0644)).To(Succeed())
+
+			_, err := tarball.ReadRelease(releasePath)
+			Expect(err).To(MatchError(fmt.Sprintf("failed to read release %s: release.MF not found", releasePath)))
+		})
+
+		It("returns an error when the file does not exist", func() {
+			_, err := tarball.ReadRelease(filepath.Join(dir, "missing.tgz"))
+			Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+		})
+	})
+})
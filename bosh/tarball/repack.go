@@ -0,0 +1,80 @@
+package tarball
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// Repack writes a copy of the gzipped tarball at path to a temporary file
+// without the named entries and returns the path of the copy.
+func Repack(path string, skip []string) (string, error) {
+	source, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer source.Close()
+
+	gzipReader, err := gzip.NewReader(source)
+	if err != nil {
+		return "", err
+	}
+	defer gzipReader.Close()
+
+	destination, err := ioutil.TempFile("", "repack")
+	if err != nil {
+		return "", err
+	}
+	defer destination.Close()
+
+	skipped := map[string]bool{}
+	for _, name := range skip {
+		skipped[entryName(name)] = true
+	}
+
+	err = copyEntries(tar.NewReader(gzipReader), destination, skipped)
+	if err != nil {
+		os.Remove(destination.Name())
+		return "", err
+	}
+
+	return destination.Name(), nil
+}
+
+func copyEntries(tarReader *tar.Reader, destination io.Writer, skipped map[string]bool) error {
+	gzipWriter := gzip.NewWriter(destination)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if skipped[entryName(header.Name)] {
+			continue
+		}
+
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(tarWriter, tarReader)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return gzipWriter.Close()
+}
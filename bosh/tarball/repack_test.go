@@ -0,0 +1,87 @@
+package tarball_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh/tarball"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Repack", func() {
+	var dir string
+
+	var entriesOf = func(path string) map[string]string {
+		file, err := os.Open(path)
+		Expect(err).NotTo(HaveOccurred())
+		defer file.Close()
+
+		gzipReader, err := gzip.NewReader(file)
+		Expect(err).NotTo(HaveOccurred())
+
+		entries := map[string]string{}
+		tarReader := tar.NewReader(gzipReader)
+		for {
+			header, err := tarReader.Next()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).NotTo(HaveOccurred())
+
+			contents, err := ioutil.ReadAll(tarReader)
+			Expect(err).NotTo(HaveOccurred())
+			entries[header.Name] = string(contents)
+		}
+
+		return entries
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "repack")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("copies the tarball without the skipped entries", func() {
+		path := filepath.Join(dir, "release.tgz")
+		Expect(ioutil.WriteFile(path, buildTarball(map[string]string{
+			"./release.MF":          "name: some-release",
+			"./packages/golang.tgz": "golang",
+			"./packages/server.tgz": "server",
+		}), 0644)).To(Succeed())
+
+		repacked, err := tarball.Repack(path, []string{"packages/golang.tgz"})
+		Expect(err).NotTo(HaveOccurred())
+		defer os.Remove(repacked)
+
+		Expect(entriesOf(repacked)).To(Equal(map[string]string{
+			"./release.MF":          "name: some-release",
+			"./packages/server.tgz": "server",
+		}))
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when the tarball does not exist", func() {
+			_, err := tarball.Repack(filepath.Join(dir, "missing.tgz"), nil)
+			Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+		})
+
+		It("returns an error when the tarball is not gzipped", func() {
+			path := filepath.Join(dir, "release.tgz")
+			Expect(ioutil.WriteFile(path, []byte("not a tarball"), 0644)).To(Succeed())
+
+			_, err := tarball.Repack(path, nil)
+			Expect(err).To(MatchError(ContainSubstring("gzip: invalid header")))
+		})
+	})
+})
@@ -0,0 +1,109 @@
+package tarball
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+type Stemcell struct {
+	Name            string                 `yaml:"name"`
+	OS              string                 `yaml:"operating_system"`
+	Version         string                 `yaml:"version"`
+	APIVersion      int                    `yaml:"api_version"`
+	SHA1            string                 `yaml:"sha1"`
+	BoshProtocol    string                 `yaml:"bosh_protocol"`
+	StemcellFormats []string               `yaml:"stemcell_formats"`
+	CloudProperties map[string]interface{} `yaml:"cloud_properties"`
+
+	TarballSHA1 string `yaml:"-"`
+
+	imageSHA1 string
+	hasImage  bool
+}
+
+type ManifestStemcell struct {
+	Alias   string `yaml:"alias"`
+	OS      string `yaml:"os"`
+	Version string `yaml:"version"`
+}
+
+func ReadStemcell(stemcellPath string) (Stemcell, error) {
+	file, err := os.Open(stemcellPath)
+	if err != nil {
+		return Stemcell{}, err
+	}
+	defer file.Close()
+
+	stemcell, err := ReadStemcellFrom(file)
+	if err != nil {
+		return Stemcell{}, fmt.Errorf("failed to read stemcell %s: %s", stemcellPath, err)
+	}
+
+	return stemcell, nil
+}
+
+func ReadStemcellFrom(contents io.Reader) (Stemcell, error) {
+	var (
+		manifest  []byte
+		imageSHA1 string
+		hasImage  bool
+	)
+
+	tarballSHA1, err := walk(contents, func(name string, contents io.Reader) error {
+		var err error
+		switch name {
+		case "stemcell.MF":
+			manifest, err = ioutil.ReadAll(contents)
+		case "image":
+			hasImage = true
+			imageSHA1, err = sha1Of(contents)
+		}
+
+		return err
+	})
+	if err != nil {
+		return Stemcell{}, err
+	}
+
+	if manifest == nil {
+		return Stemcell{}, errors.New("stemcell.MF not found")
+	}
+
+	var stemcell Stemcell
+	err = yaml.Unmarshal(manifest, &stemcell)
+	if err != nil {
+		return Stemcell{}, fmt.Errorf("failed to parse stemcell.MF: %s", err)
+	}
+
+	stemcell.TarballSHA1 = tarballSHA1
+	stemcell.imageSHA1 = imageSHA1
+	stemcell.hasImage = hasImage
+
+	return stemcell, nil
+}
+
+func (s Stemcell) ManifestEntry(alias string) ManifestStemcell {
+	return ManifestStemcell{
+		Alias:   alias,
+		OS:      s.OS,
+		Version: s.Version,
+	}
+}
+
+// Validate checks the image against the sha1 in stemcell.MF. Light stemcells
+// carry no image and only need a name, os and version.
+func (s Stemcell) Validate() error {
+	switch {
+	case s.Name == "" || s.OS == "" || s.Version == "":
+		return errors.New("stemcell.MF must have a name, operating_system and version")
+	case s.hasImage && s.SHA1 != "" && s.imageSHA1 != s.SHA1:
+		return fmt.Errorf("stemcell %s/%s image has sha1 %s, expected %s", s.Name, s.Version, s.imageSHA1, s.SHA1)
+	}
+
+	return nil
+}
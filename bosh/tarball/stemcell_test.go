@@ -0,0 +1,124 @@
+package tarball_test
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh/tarball"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stemcell", func() {
+	var files map[string]string
+
+	BeforeEach(func() {
+		files = map[string]string{
+			"./stemcell.MF": fmt.Sprintf(`---
+name: bosh-warden-boshlite-ubuntu-xenial-go_agent
+operating_system: ubuntu-xenial
+version: 621.74
+api_version: 3
+sha1: %x
+bosh_protocol: 1
+stemcell_formats:
+- warden-tgz
+cloud_properties:
+  infrastructure: warden
+`, sha1.Sum([]byte("some-image"))),
+			"./image": "some-image",
+		}
+	})
+
+	Describe("ReadStemcellFrom", func() {
+		It("parses stemcell.MF", func() {
+			contents := buildTarball(files)
+
+			stemcell, err := tarball.ReadStemcellFrom(bytes.NewReader(contents))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stemcell.Name).To(Equal("bosh-warden-boshlite-ubuntu-xenial-go_agent"))
+			Expect(stemcell.OS).To(Equal("ubuntu-xenial"))
+			Expect(stemcell.Version).To(Equal("621.74"))
+			Expect(stemcell.APIVersion).To(Equal(3))
+			Expect(stemcell.SHA1).To(Equal(fmt.Sprintf("%x", sha1.Sum([]byte("some-image")))))
+			Expect(stemcell.BoshProtocol).To(Equal("1"))
+			Expect(stemcell.StemcellFormats).To(Equal([]string{"warden-tgz"}))
+			Expect(stemcell.CloudProperties).To(HaveKeyWithValue("infrastructure", "warden"))
+			Expect(stemcell.TarballSHA1).To(Equal(fmt.Sprintf("%x", sha1.Sum(contents))))
+
+			Expect(stemcell.Validate()).To(Succeed())
+		})
+
+		It("derives the manifest stemcells entry", func() {
+			stemcell, err := tarball.ReadStemcellFrom(bytes.NewReader(buildTarball(files)))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stemcell.ManifestEntry("default")).To(Equal(tarball.ManifestStemcell{
+				Alias:   "default",
+				OS:      "ubuntu-xenial",
+				Version: "621.74",
+			}))
+		})
+
+		It("accepts light stemcells without an image", func() {
+			delete(files, "./image")
+
+			stemcell, err := tarball.ReadStemcellFrom(bytes.NewReader(buildTarball(files)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stemcell.Validate()).To(Succeed())
+		})
+
+		It("rejects an image that does not match the sha1", func() {
+			files["./image"] = "tampered"
+
+			stemcell, err := tarball.ReadStemcellFrom(bytes.NewReader(buildTarball(files)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stemcell.Validate()).To(MatchError(fmt.Sprintf("stemcell bosh-warden-boshlite-ubuntu-xenial-go_agent/621.74 image has sha1 %x, expected %x", sha1.Sum([]byte("tampered")), sha1.Sum([]byte("some-image")))))
+		})
+
+		It("rejects a stemcell.MF without a name, os or version", func() {
+			files["./stemcell.MF"] = "name: some-stemcell\n"
+
+			stemcell, err := tarball.ReadStemcellFrom(bytes.NewReader(buildTarball(files)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stemcell.Validate()).To(MatchError("stemcell.MF must have a name, operating_system and version"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when stemcell.MF is missing", func() {
+				delete(files, "./stemcell.MF")
+
+				_, err := tarball.ReadStemcellFrom(bytes.NewReader(buildTarball(files)))
+				Expect(err).To(MatchError("stemcell.MF not found"))
+			})
+
+			It("returns an error when stemcell.MF is malformed", func() {
+				files["./stemcell.MF"] = "%%%"
+
+				_, err := tarball.ReadStemcellFrom(bytes.NewReader(buildTarball(files)))
+				Expect(err).To(MatchError(ContainSubstring("failed to parse stemcell.MF")))
+			})
+		})
+	})
+
+	Describe("ReadStemcell", func() {
+		It("reads the stemcell from a path", func() {
+			dir, err := ioutil.TempDir("", "stemcell")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			stemcellPath := filepath.Join(dir, "stemcell.tgz")
+			Expect(ioutil.WriteFile(stemcellPath, buildTarball(files), 0644)).To(Succeed())
+
+			stemcell, err := tarball.ReadStemcell(stemcellPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stemcell.OS).To(Equal("ubuntu-xenial"))
+		})
+	})
+})
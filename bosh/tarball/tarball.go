@@ -0,0 +1,69 @@
+package tarball
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+type entryHandler func(name string, contents io.Reader) error
+
+// walk calls handler for every regular file in the gzipped tarball and
+// returns the sha1 of the tarball itself. Directories are never written to
+// disk.
+func walk(tarball io.Reader, handler entryHandler) (string, error) {
+	hash := sha1.New()
+	reader := io.TeeReader(tarball, hash)
+
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return "", err
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		err = handler(entryName(header.Name), tarReader)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	_, err = io.Copy(ioutil.Discard, reader)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+func entryName(name string) string {
+	return strings.TrimPrefix(path.Clean(name), "./")
+}
+
+func sha1Of(contents io.Reader) (string, error) {
+	hash := sha1.New()
+	_, err := io.Copy(hash, contents)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
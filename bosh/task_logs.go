@@ -0,0 +1,62 @@
+package bosh
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var taskArtifactTypes = []string{"event", "debug", "cpi"}
+
+func (c Client) GetTaskDebugLog(taskId int) ([]byte, error) {
+	return c.GetTaskDebugLogContext(context.Background(), taskId)
+}
+
+func (c Client) GetTaskDebugLogContext(ctx context.Context, taskId int) ([]byte, error) {
+	return c.taskOutputFrom(ctx, taskId, "debug", 0)
+}
+
+func (c Client) GetTaskCPILog(taskId int) ([]byte, error) {
+	return c.GetTaskCPILogContext(context.Background(), taskId)
+}
+
+func (c Client) GetTaskCPILogContext(ctx context.Context, taskId int) ([]byte, error) {
+	return c.taskOutputFrom(ctx, taskId, "cpi", 0)
+}
+
+func (c Client) SaveTaskArtifacts(taskId int, dir string) error {
+	return c.SaveTaskArtifactsContext(context.Background(), taskId, dir)
+}
+
+func (c Client) SaveTaskArtifactsContext(ctx context.Context, taskId int, dir string) error {
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	for _, outputType := range taskArtifactTypes {
+		output, err := c.taskOutputFrom(ctx, taskId, outputType, 0)
+		if err != nil {
+			return err
+		}
+
+		err = ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("task-%d-%s.log", taskId, outputType)), output, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveTaskArtifacts is best effort: a failure to capture the logs must not
+// hide the task failure that triggered it.
+func (c Client) saveTaskArtifacts(ctx context.Context, taskId int) {
+	if c.config.TaskArtifactsDir == "" {
+		return
+	}
+
+	c.SaveTaskArtifactsContext(ctx, taskId, c.config.TaskArtifactsDir)
+}
@@ -0,0 +1,160 @@
+package bosh_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("task logs", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/deployments":
+				w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/1", r.Host))
+				w.WriteHeader(http.StatusFound)
+			case "/tasks/1":
+				w.Write([]byte(`{"id": 1, "state": "error", "result": "some-error-message"}`))
+			case "/tasks/1/output":
+				Expect(r.Method).To(Equal("GET"))
+
+				username, password, ok := r.BasicAuth()
+				Expect(ok).To(BeTrue())
+				Expect(username).To(Equal("some-username"))
+				Expect(password).To(Equal("some-password"))
+
+				switch r.URL.RawQuery {
+				case "type=event":
+					w.Write([]byte(`{"error": {"code": 100, "message": "some-better-error-message"}}` + "\n"))
+				case "type=debug":
+					w.Write([]byte("some-debug-log\n"))
+				case "type=cpi":
+					w.Write([]byte("some-cpi-log\n"))
+				default:
+					Fail("unexpected output type")
+				}
+			default:
+				Fail("could not match any URL endpoints")
+			}
+		}))
+	})
+
+	Describe("GetTaskDebugLog", func() {
+		It("returns the debug log of the task", func() {
+			client := bosh.NewClient(bosh.Config{
+				URL:      server.URL,
+				Username: "some-username",
+				Password: "some-password",
+			})
+
+			log, err := client.GetTaskDebugLog(1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(log)).To(Equal("some-debug-log\n"))
+		})
+
+		It("errors on an unexpected status code with a body", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+				w.Write([]byte("More Info"))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			_, err := client.GetTaskDebugLog(1)
+			Expect(err).To(MatchError("unexpected response 502 Bad Gateway:\nMore Info"))
+		})
+	})
+
+	Describe("GetTaskCPILog", func() {
+		It("returns the cpi log of the task", func() {
+			client := bosh.NewClient(bosh.Config{
+				URL:      server.URL,
+				Username: "some-username",
+				Password: "some-password",
+			})
+
+			log, err := client.GetTaskCPILog(1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(log)).To(Equal("some-cpi-log\n"))
+		})
+	})
+
+	Describe("SaveTaskArtifacts", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "task-artifacts")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("writes the event, debug and cpi logs to the directory", func() {
+			client := bosh.NewClient(bosh.Config{
+				URL:      server.URL,
+				Username: "some-username",
+				Password: "some-password",
+			})
+
+			err := client.SaveTaskArtifacts(1, filepath.Join(dir, "nested"))
+			Expect(err).NotTo(HaveOccurred())
+
+			contents, err := ioutil.ReadFile(filepath.Join(dir, "nested", "task-1-debug.log"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("some-debug-log\n"))
+
+			contents, err = ioutil.ReadFile(filepath.Join(dir, "nested", "task-1-cpi.log"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("some-cpi-log\n"))
+
+			contents, err = ioutil.ReadFile(filepath.Join(dir, "nested", "task-1-event.log"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(ContainSubstring("some-better-error-message"))
+		})
+
+		It("saves the logs of failed tasks when TaskArtifactsDir is configured", func() {
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				Username:            "some-username",
+				Password:            "some-password",
+				TaskPollingInterval: time.Nanosecond,
+				TaskArtifactsDir:    dir,
+			})
+
+			_, err := client.Deploy([]byte("some-yaml"))
+			Expect(err).To(MatchError("bosh task 1 failed with an error status \"some-error-message\":\n  - task error: 100 has occurred: some-better-error-message"))
+
+			Expect(filepath.Join(dir, "task-1-event.log")).To(BeAnExistingFile())
+			Expect(filepath.Join(dir, "task-1-debug.log")).To(BeAnExistingFile())
+			Expect(filepath.Join(dir, "task-1-cpi.log")).To(BeAnExistingFile())
+		})
+
+		It("returns an error when the directory cannot be created", func() {
+			file := filepath.Join(dir, "some-file")
+			Expect(ioutil.WriteFile(file, []byte{}, 0644)).To(Succeed())
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			err := client.SaveTaskArtifacts(1, file)
+			Expect(err).To(MatchError(ContainSubstring("not a directory")))
+		})
+	})
+})
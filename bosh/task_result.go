@@ -1,13 +1,18 @@
 package bosh
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 )
 
 func (c Client) TaskResult(taskId int) (map[string]interface{}, error) {
-	request, err := http.NewRequest("GET", fmt.Sprintf("%s/tasks/%d/output?type=result", c.config.URL, taskId), nil)
+	return c.TaskResultContext(context.Background(), taskId)
+}
+
+func (c Client) TaskResultContext(ctx context.Context, taskId int) (map[string]interface{}, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/tasks/%d/output?type=result", c.config.URL, taskId), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -24,7 +29,7 @@ func (c Client) TaskResult(taskId int) (map[string]interface{}, error) {
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected response %s:\n%s", response.Status, body)
+		return nil, newDirectorError(response, body)
 	}
 
 	result := map[string]interface{}{}
@@ -0,0 +1,129 @@
+package bosh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type TaskFilter struct {
+	States     []string
+	Deployment string
+	Limit      int
+	Verbose    int
+}
+
+func (f TaskFilter) query() url.Values {
+	query := url.Values{}
+	if len(f.States) > 0 {
+		query.Set("state", strings.Join(f.States, ","))
+	}
+
+	if f.Deployment != "" {
+		query.Set("deployment", f.Deployment)
+	}
+
+	if f.Limit > 0 {
+		query.Set("limit", strconv.Itoa(f.Limit))
+	}
+
+	if f.Verbose > 0 {
+		query.Set("verbose", strconv.Itoa(f.Verbose))
+	}
+
+	return query
+}
+
+func (c Client) Tasks(filter TaskFilter) ([]Task, error) {
+	return c.TasksContext(context.Background(), filter)
+}
+
+func (c Client) TasksContext(ctx context.Context, filter TaskFilter) ([]Task, error) {
+	location := fmt.Sprintf("%s/tasks", c.config.URL)
+	if query := filter.query(); len(query) > 0 {
+		location = fmt.Sprintf("%s?%s", location, query.Encode())
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "GET", location, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.makeRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := bodyReader(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, newDirectorError(response, body)
+	}
+
+	var tasks []Task
+	err = json.Unmarshal(body, &tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func (c Client) Task(taskId int) (Task, error) {
+	return c.TaskContext(context.Background(), taskId)
+}
+
+func (c Client) TaskContext(ctx context.Context, taskId int) (Task, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/tasks/%d", c.config.URL, taskId), nil)
+	if err != nil {
+		return Task{}, err
+	}
+
+	response, err := c.makeRequest(request)
+	if err != nil {
+		return Task{}, err
+	}
+
+	body, err := bodyReader(response.Body)
+	if err != nil {
+		return Task{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return Task{}, newDirectorError(response, body)
+	}
+
+	var task Task
+	err = json.Unmarshal(body, &task)
+	if err != nil {
+		return Task{}, err
+	}
+
+	return task, nil
+}
+
+func (c Client) CancelTask(taskId int) error {
+	return c.CancelTaskContext(context.Background(), taskId)
+}
+
+func (c Client) CancelTaskContext(ctx context.Context, taskId int) error {
+	return c.cancelTask(ctx, taskId)
+}
+
+func (c Client) WaitForTask(taskId int) error {
+	return c.WaitForTaskContext(context.Background(), taskId)
+}
+
+func (c Client) WaitForTaskContext(ctx context.Context, taskId int) error {
+	_, err := c.pollTask(ctx, fmt.Sprintf("%s/tasks/%d", c.config.URL, taskId), false)
+	return err
+}
@@ -0,0 +1,299 @@
+package bosh_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tasks", func() {
+	Describe("Tasks", func() {
+		It("lists the tasks matching the given filter", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/tasks"))
+				Expect(r.Method).To(Equal("GET"))
+				Expect(r.URL.Query().Get("state")).To(Equal("queued,processing"))
+				Expect(r.URL.Query().Get("deployment")).To(Equal("some-deployment"))
+				Expect(r.URL.Query().Get("limit")).To(Equal("2"))
+				Expect(r.URL.Query().Get("verbose")).To(Equal("1"))
+
+				username, password, ok := r.BasicAuth()
+				Expect(ok).To(BeTrue())
+				Expect(username).To(Equal("some-username"))
+				Expect(password).To(Equal("some-password"))
+
+				w.Write([]byte(`[
+					{"id": 2, "state": "processing", "description": "create deployment", "timestamp": 1500000100, "started_at": 1500000050, "result": null, "user": "admin", "deployment": "some-deployment", "context_id": "some-context"},
+					{"id": 1, "state": "queued", "description": "run errand", "timestamp": 1500000000, "started_at": null, "result": null, "user": "admin", "deployment": "some-deployment", "context_id": ""}
+				]`))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:      server.URL,
+				Username: "some-username",
+				Password: "some-password",
+			})
+
+			tasks, err := client.Tasks(bosh.TaskFilter{
+				States:     []string{"queued", "processing"},
+				Deployment: "some-deployment",
+				Limit:      2,
+				Verbose:    1,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tasks).To(Equal([]bosh.Task{
+				{
+					Id:          2,
+					State:       "processing",
+					Description: "create deployment",
+					Timestamp:   1500000100,
+					StartedAt:   1500000050,
+					User:        "admin",
+					Deployment:  "some-deployment",
+					ContextID:   "some-context",
+				},
+				{
+					Id:          1,
+					State:       "queued",
+					Description: "run errand",
+					Timestamp:   1500000000,
+					User:        "admin",
+					Deployment:  "some-deployment",
+				},
+			}))
+		})
+
+		It("omits unset filters", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/tasks"))
+				Expect(r.URL.RawQuery).To(BeEmpty())
+
+				w.Write([]byte(`[]`))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			tasks, err := client.Tasks(bosh.TaskFilter{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tasks).To(BeEmpty())
+		})
+
+		Context("failure cases", func() {
+			It("errors on an unexpected status code with a body", func() {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusBadGateway)
+					w.Write([]byte("More Info"))
+				}))
+
+				client := bosh.NewClient(bosh.Config{
+					URL: server.URL,
+				})
+
+				_, err := client.Tasks(bosh.TaskFilter{})
+				Expect(err).To(MatchError("unexpected response 502 Bad Gateway:\nMore Info"))
+			})
+
+			It("errors on a bogus response body", func() {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(`[]`))
+				}))
+
+				client := bosh.NewClient(bosh.Config{
+					URL: server.URL,
+				})
+
+				bosh.SetBodyReader(func(io.Reader) ([]byte, error) {
+					return nil, errors.New("a bad read happened")
+				})
+
+				_, err := client.Tasks(bosh.TaskFilter{})
+				Expect(err).To(MatchError("a bad read happened"))
+			})
+
+			It("errors on malformed JSON", func() {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(`%%%%%%%%`))
+				}))
+
+				client := bosh.NewClient(bosh.Config{
+					URL: server.URL,
+				})
+
+				_, err := client.Tasks(bosh.TaskFilter{})
+				Expect(err).To(MatchError(ContainSubstring("invalid character")))
+			})
+		})
+	})
+
+	Describe("Task", func() {
+		It("returns the task with the given id", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/tasks/3"))
+				Expect(r.Method).To(Equal("GET"))
+
+				w.Write([]byte(`{"id": 3, "state": "done", "description": "delete deployment", "timestamp": 1500000100, "started_at": 1500000050, "result": "/deployments/some-deployment", "user": "admin", "deployment": "some-deployment", "context_id": "some-context"}`))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			task, err := client.Task(3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(task).To(Equal(bosh.Task{
+				Id:          3,
+				State:       "done",
+				Description: "delete deployment",
+				Timestamp:   1500000100,
+				StartedAt:   1500000050,
+				Result:      "/deployments/some-deployment",
+				User:        "admin",
+				Deployment:  "some-deployment",
+				ContextID:   "some-context",
+			}))
+		})
+
+		It("errors on an unexpected status code with a body", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte("More Info"))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			_, err := client.Task(3)
+			Expect(err).To(MatchError("unexpected response 404 Not Found:\nMore Info"))
+		})
+	})
+
+	Describe("CancelTask", func() {
+		It("cancels the task with the given id", func() {
+			var cancelled bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/tasks/3"))
+				Expect(r.Method).To(Equal("DELETE"))
+
+				cancelled = true
+				w.WriteHeader(http.StatusNoContent)
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			err := client.CancelTask(3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cancelled).To(BeTrue())
+		})
+
+		It("errors on an unexpected status code with a body", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte("More Info"))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			err := client.CancelTask(3)
+			Expect(err).To(MatchError("unexpected response 400 Bad Request:\nMore Info"))
+		})
+	})
+
+	Describe("WaitForTask", func() {
+		It("polls the task until it is done", func() {
+			callCount := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/tasks/3"))
+				Expect(r.Method).To(Equal("GET"))
+
+				if callCount == 2 {
+					w.Write([]byte(`{"id": 3, "state": "done"}`))
+				} else {
+					w.Write([]byte(`{"id": 3, "state": "processing"}`))
+				}
+				callCount++
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			err := client.WaitForTask(3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(callCount).To(Equal(3))
+		})
+
+		It("returns an error when the task is cancelled", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"id": 3, "state": "cancelled"}`))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			err := client.WaitForTask(3)
+			Expect(err).To(MatchError("bosh task was cancelled"))
+		})
+
+		It("returns an error when the task cannot be fetched", func() {
+			var callCount int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				callCount++
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte("More Info"))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			err := client.WaitForTask(3)
+			Expect(err).To(MatchError("unexpected response 404 Not Found:\nMore Info"))
+			Expect(callCount).To(Equal(1))
+		})
+
+		It("stops waiting without cancelling the task when the context is done", func() {
+			var cancelled bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "DELETE" {
+					cancelled = true
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				w.Write([]byte(`{"id": 3, "state": "processing"}`))
+			}))
+			defer server.Close()
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Millisecond,
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			err := client.WaitForTaskContext(ctx, 3)
+			Expect(err).To(MatchError("context deadline exceeded"))
+			Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+			Expect(cancelled).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,157 @@
+package bosh_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func generateCertificate(commonName string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).NotTo(HaveOccurred())
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func certificatePEM(server *httptest.Server) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+var _ = Describe("TLS", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"uuid": "some-uuid", "cpi": "some-cpi"}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("trusts the director CA certificate", func() {
+		client := bosh.NewClient(bosh.Config{
+			URL:            server.URL,
+			DirectorCACert: certificatePEM(server),
+		})
+
+		info, err := client.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.UUID).To(Equal("some-uuid"))
+	})
+
+	It("trusts every certificate in the director CA bundle", func() {
+		otherCert, _ := generateCertificate("some-other-ca")
+
+		client := bosh.NewClient(bosh.Config{
+			URL:            server.URL,
+			DirectorCACert: otherCert + certificatePEM(server),
+		})
+
+		_, err := client.Info()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("does not trust a director signed by a different CA", func() {
+		otherCert, _ := generateCertificate("some-other-ca")
+
+		client := bosh.NewClient(bosh.Config{
+			URL:            server.URL,
+			DirectorCACert: otherCert,
+		})
+
+		_, err := client.Info()
+		Expect(err).To(MatchError(ContainSubstring("certificate")))
+	})
+
+	It("returns an error when the director CA certificate is not PEM", func() {
+		client := bosh.NewClient(bosh.Config{
+			URL:            server.URL,
+			DirectorCACert: "some-garbage",
+		})
+
+		_, err := client.Locks()
+		Expect(err).To(MatchError(ContainSubstring("failed to parse director CA certificate: no PEM certificates found")))
+	})
+
+	Context("when the director requires a client certificate", func() {
+		var (
+			mtlsServer *httptest.Server
+			clientCert string
+			clientKey  string
+		)
+
+		BeforeEach(func() {
+			clientCert, clientKey = generateCertificate("some-client")
+
+			mtlsServer = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.TLS.PeerCertificates).To(HaveLen(1))
+				Expect(r.TLS.PeerCertificates[0].Subject.CommonName).To(Equal("some-client"))
+
+				w.Write([]byte(`[]`))
+			}))
+			mtlsServer.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+			mtlsServer.StartTLS()
+		})
+
+		AfterEach(func() {
+			mtlsServer.Close()
+		})
+
+		It("presents the configured client certificate", func() {
+			client := bosh.NewClient(bosh.Config{
+				URL:            mtlsServer.URL,
+				DirectorCACert: certificatePEM(mtlsServer),
+				ClientCert:     clientCert,
+				ClientKey:      clientKey,
+			})
+
+			_, err := client.Locks()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns an error when the client certificate cannot be loaded", func() {
+			client := bosh.NewClient(bosh.Config{
+				URL:            mtlsServer.URL,
+				DirectorCACert: certificatePEM(mtlsServer),
+				ClientCert:     clientCert,
+			})
+
+			_, err := client.Locks()
+			Expect(err).To(MatchError(ContainSubstring("failed to load client certificate")))
+		})
+	})
+})
@@ -0,0 +1,128 @@
+package bosh
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const defaultUAAClientID = "bosh_cli"
+
+type uaaAuth struct {
+	mutex       sync.Mutex
+	token       *oauth2.Token
+	tokenSource func(context.Context, *oauth2.Token) oauth2.TokenSource
+}
+
+func (c Client) uaaToken(ctx context.Context) (*oauth2.Token, error) {
+	c.uaa.mutex.Lock()
+	defer c.uaa.mutex.Unlock()
+
+	if c.uaa.tokenSource == nil {
+		tokenSource, token, err := c.newUAATokenSource(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		c.uaa.tokenSource = tokenSource
+		c.uaa.token = token
+	}
+
+	token, err := c.uaa.tokenSource(ctx, c.uaa.token).Token()
+	if err != nil {
+		return nil, err
+	}
+
+	c.uaa.token = token
+
+	return token, nil
+}
+
+// newUAATokenSource returns a constructor rather than a single token source
+// because oauth2 token sources hold on to the context they were built with,
+// and every token fetch should be bound to the request that triggered it.
+func (c Client) newUAATokenSource(ctx context.Context) (func(context.Context, *oauth2.Token) oauth2.TokenSource, *oauth2.Token, error) {
+	uaaURL, err := c.uaaURL(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokenURL := fmt.Sprintf("%s/oauth/token", strings.TrimSuffix(uaaURL, "/"))
+
+	if !c.config.UAAPasswordGrant && c.config.UAARefreshToken == "" {
+		config := &clientcredentials.Config{
+			ClientID:     c.config.Username,
+			ClientSecret: c.config.Password,
+			TokenURL:     tokenURL,
+		}
+
+		return func(ctx context.Context, token *oauth2.Token) oauth2.TokenSource {
+			return oauth2.ReuseTokenSource(token, config.TokenSource(c.uaaTokenContext(ctx)))
+		}, nil, nil
+	}
+
+	clientID := c.config.UAAClientID
+	if clientID == "" {
+		clientID = defaultUAAClientID
+	}
+
+	config := &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: c.config.UAAClientSecret,
+		Endpoint: oauth2.Endpoint{
+			TokenURL: tokenURL,
+		},
+	}
+
+	tokenSource := func(ctx context.Context, token *oauth2.Token) oauth2.TokenSource {
+		return config.TokenSource(c.uaaTokenContext(ctx), token)
+	}
+
+	if !c.config.UAAPasswordGrant {
+		return tokenSource, &oauth2.Token{RefreshToken: c.config.UAARefreshToken}, nil
+	}
+
+	token, err := config.PasswordCredentialsToken(c.uaaTokenContext(ctx), c.config.Username, c.config.Password)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tokenSource, token, nil
+}
+
+func (c Client) uaaTokenContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, c.httpClient)
+}
+
+func (c Client) uaaURL(ctx context.Context) (string, error) {
+	if c.config.UAAURL != "" {
+		return c.config.UAAURL, nil
+	}
+
+	info, err := c.InfoContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if uaaURL, ok := info.UserAuthentication.Options["url"].(string); ok && uaaURL != "" {
+		return uaaURL, nil
+	}
+
+	urlParts, err := url.Parse(c.config.URL)
+	if err != nil {
+		return "", err
+	}
+
+	boshHost, _, err := net.SplitHostPort(urlParts.Host)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("https://%s:8443", boshHost), nil
+}
@@ -0,0 +1,205 @@
+package bosh_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UAA", func() {
+	var (
+		server        *httptest.Server
+		tokenRequests []map[string]string
+		expiresIn     int
+	)
+
+	BeforeEach(func() {
+		tokenRequests = []map[string]string{}
+		expiresIn = 3600
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/info":
+				w.Write([]byte(fmt.Sprintf(`{"user_authentication": {"type": "uaa", "options": {"url": "http://%s/uaa"}}}`, r.Host)))
+			case "/uaa/oauth/token":
+				Expect(r.Method).To(Equal("POST"))
+				Expect(r.ParseForm()).To(Succeed())
+
+				clientID, clientSecret, _ := r.BasicAuth()
+				tokenRequests = append(tokenRequests, map[string]string{
+					"grant_type":    r.PostForm.Get("grant_type"),
+					"username":      r.PostForm.Get("username"),
+					"password":      r.PostForm.Get("password"),
+					"refresh_token": r.PostForm.Get("refresh_token"),
+					"client_id":     clientID,
+					"client_secret": clientSecret,
+				})
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(fmt.Sprintf(`{"access_token": "some-token-%d", "refresh_token": "some-refresh-token", "token_type": "bearer", "expires_in": %d}`, len(tokenRequests), expiresIn)))
+			case "/locks":
+				Expect(r.Header.Get("Authorization")).To(HavePrefix("Bearer some-token-"))
+				w.Write([]byte(`[]`))
+			default:
+				Fail("could not match any URL endpoints")
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("discovers the UAA from the director and reuses the client credentials token", func() {
+		client := bosh.NewClient(bosh.Config{
+			URL:      server.URL,
+			Username: "some-client",
+			Password: "some-client-secret",
+			UAA:      true,
+		})
+
+		for i := 0; i < 5; i++ {
+			_, err := client.Locks()
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(tokenRequests).To(Equal([]map[string]string{
+			{
+				"grant_type":    "client_credentials",
+				"username":      "",
+				"password":      "",
+				"refresh_token": "",
+				"client_id":     "some-client",
+				"client_secret": "some-client-secret",
+			},
+		}))
+	})
+
+	It("uses the configured UAA URL", func() {
+		client := bosh.NewClient(bosh.Config{
+			URL:      server.URL,
+			Username: "some-client",
+			Password: "some-client-secret",
+			UAA:      true,
+			UAAURL:   server.URL + "/uaa/",
+		})
+
+		_, err := client.Locks()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokenRequests).To(HaveLen(1))
+	})
+
+	It("logs in with the password grant and refreshes the token before it expires", func() {
+		expiresIn = 1
+
+		client := bosh.NewClient(bosh.Config{
+			URL:              server.URL,
+			Username:         "some-user",
+			Password:         "some-password",
+			UAA:              true,
+			UAAPasswordGrant: true,
+		})
+
+		_, err := client.Locks()
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.Locks()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(tokenRequests).To(Equal([]map[string]string{
+			{
+				"grant_type":    "password",
+				"username":      "some-user",
+				"password":      "some-password",
+				"refresh_token": "",
+				"client_id":     "bosh_cli",
+				"client_secret": "",
+			},
+			{
+				"grant_type":    "refresh_token",
+				"username":      "",
+				"password":      "",
+				"refresh_token": "some-refresh-token",
+				"client_id":     "bosh_cli",
+				"client_secret": "",
+			},
+			{
+				"grant_type":    "refresh_token",
+				"username":      "",
+				"password":      "",
+				"refresh_token": "some-refresh-token",
+				"client_id":     "bosh_cli",
+				"client_secret": "",
+			},
+		}))
+	})
+
+	It("logs in with a refresh token", func() {
+		client := bosh.NewClient(bosh.Config{
+			URL:             server.URL,
+			UAA:             true,
+			UAARefreshToken: "some-saved-refresh-token",
+		})
+
+		_, err := client.Locks()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(tokenRequests).To(Equal([]map[string]string{
+			{
+				"grant_type":    "refresh_token",
+				"username":      "",
+				"password":      "",
+				"refresh_token": "some-saved-refresh-token",
+				"client_id":     "bosh_cli",
+				"client_secret": "",
+			},
+		}))
+	})
+
+	It("stops fetching the token when the request context is done", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(200 * time.Millisecond):
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		client := bosh.NewClient(bosh.Config{
+			URL:    server.URL,
+			UAA:    true,
+			UAAURL: server.URL,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := client.LocksContext(ctx)
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+	})
+
+	It("returns an error when the token cannot be fetched", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		client := bosh.NewClient(bosh.Config{
+			URL:    server.URL,
+			UAA:    true,
+			UAAURL: server.URL,
+		})
+
+		_, err := client.Locks()
+		Expect(err).To(MatchError(ContainSubstring("401")))
+	})
+})
@@ -2,12 +2,17 @@ package bosh
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 )
 
 func (c Client) UpdateCloudConfig(cloudConfig []byte) error {
-	request, err := http.NewRequest("POST", fmt.Sprintf("%s/cloud_configs", c.config.URL), bytes.NewBuffer(cloudConfig))
+	return c.UpdateCloudConfigContext(context.Background(), cloudConfig)
+}
+
+func (c Client) UpdateCloudConfigContext(ctx context.Context, cloudConfig []byte) error {
+	request, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/cloud_configs", c.config.URL), bytes.NewBuffer(cloudConfig))
 	if err != nil {
 		return err
 	}
@@ -26,7 +31,7 @@ func (c Client) UpdateCloudConfig(cloudConfig []byte) error {
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusCreated {
-		return fmt.Errorf("unexpected response %d %s:\n%s", response.StatusCode, http.StatusText(response.StatusCode), body)
+		return newDirectorError(response, body)
 	}
 
 	return nil
@@ -0,0 +1,145 @@
+package bosh
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	UploadStageSending    = "sending"
+	UploadStageProcessing = "processing"
+)
+
+var uploadProgressInterval = time.Second
+
+type UploadProgress struct {
+	Stage          string
+	BytesSent      int64
+	TotalBytes     int64
+	BytesPerSecond float64
+	Elapsed        time.Duration
+	TaskID         int
+	Event          TaskOutput
+}
+
+func (p UploadProgress) Percent() float64 {
+	if p.TotalBytes <= 0 {
+		return 0
+	}
+
+	return float64(p.BytesSent) * 100 / float64(p.TotalBytes)
+}
+
+// ProgressWriter returns an UploadOptions.Progress callback that prints one
+// line per report, which is enough output to keep CI tools from assuming
+// the upload has hung.
+func ProgressWriter(writer io.Writer) func(UploadProgress) {
+	var mutex sync.Mutex
+
+	return func(progress UploadProgress) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		switch progress.Stage {
+		case UploadStageSending:
+			fmt.Fprintf(writer, "uploading: %s / %s (%.0f%%, %s/s)\n",
+				formatBytes(float64(progress.BytesSent)),
+				formatBytes(float64(progress.TotalBytes)),
+				progress.Percent(),
+				formatBytes(progress.BytesPerSecond))
+		case UploadStageProcessing:
+			event := progress.Event
+			description := strings.TrimSpace(strings.Join([]string{event.Stage, event.Task}, " > "))
+			if len(event.Tags) > 0 {
+				description = fmt.Sprintf("%s [%s]", description, strings.Join(event.Tags, ", "))
+			}
+			fmt.Fprintf(writer, "task %d: %s (%s)\n", progress.TaskID, description, event.State)
+		}
+	}
+}
+
+func formatBytes(bytes float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB"}
+
+	unit := 0
+	for bytes >= 1024 && unit < len(units)-1 {
+		bytes /= 1024
+		unit++
+	}
+
+	return fmt.Sprintf("%.1f %s", bytes, units[unit])
+}
+
+type progressReader struct {
+	io.Reader
+	total    int64
+	progress func(UploadProgress)
+
+	sent       int64
+	started    time.Time
+	lastReport time.Time
+}
+
+func newProgressReader(reader io.Reader, total int64, progress func(UploadProgress)) *progressReader {
+	now := time.Now()
+
+	return &progressReader{
+		Reader:     reader,
+		total:      total,
+		progress:   progress,
+		started:    now,
+		lastReport: now,
+	}
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.sent += int64(n)
+
+	now := time.Now()
+	finished := err == io.EOF || (r.total > 0 && r.sent >= r.total)
+	if n > 0 && (finished || now.Sub(r.lastReport) >= uploadProgressInterval) {
+		r.lastReport = now
+		r.report(now)
+	}
+
+	return n, err
+}
+
+func (r *progressReader) report(now time.Time) {
+	elapsed := now.Sub(r.started)
+
+	var throughput float64
+	if elapsed > 0 {
+		throughput = float64(r.sent) / elapsed.Seconds()
+	}
+
+	r.progress(UploadProgress{
+		Stage:          UploadStageSending,
+		BytesSent:      r.sent,
+		TotalBytes:     r.total,
+		BytesPerSecond: throughput,
+		Elapsed:        elapsed,
+	})
+}
+
+func (c Client) watchUploadTask(ctx context.Context, location string, progress func(UploadProgress)) (int, error) {
+	taskId, err := taskIDFromLocation(location)
+	if err != nil {
+		return 0, err
+	}
+
+	err = c.watchTask(ctx, taskId, func(event TaskOutput) {
+		progress(UploadProgress{
+			Stage:  UploadStageProcessing,
+			TaskID: taskId,
+			Event:  event,
+		})
+	}, true)
+
+	return taskId, err
+}
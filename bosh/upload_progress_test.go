@@ -0,0 +1,204 @@
+package bosh_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("upload progress", func() {
+	var (
+		server    *httptest.Server
+		taskState string
+		reports   []bosh.UploadProgress
+		contents  = strings.Repeat("I am a banana!", 10000)
+	)
+
+	BeforeEach(func() {
+		bosh.SetUploadProgressInterval(0)
+
+		taskState = "done"
+		reports = []bosh.UploadProgress{}
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/releases", "/stemcells":
+				body, err := ioutil.ReadAll(r.Body)
+				Expect(err).NotTo(HaveOccurred())
+				if r.Header.Get("Content-Type") == "application/x-compressed" {
+					Expect(string(body)).To(Equal(contents))
+				}
+
+				w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/2", r.Host))
+				w.WriteHeader(http.StatusFound)
+			case "/tasks/2":
+				w.Write([]byte(fmt.Sprintf(`{"id": 2, "state": %q, "result": "some-result"}`, taskState)))
+			case "/tasks/2/output":
+				Expect(r.URL.RawQuery).To(Equal("type=event"))
+				w.Write([]byte(`{"time":1,"stage":"Extracting release","tags":[],"total":1,"task":"Extracting release","index":1,"state":"started","progress":0}
+{"time":2,"stage":"Extracting release","tags":[],"total":1,"task":"Extracting release","index":1,"state":"finished","progress":100}
+`))
+			default:
+				Fail(fmt.Sprintf("unhandled request to %s", r.URL.Path))
+			}
+		}))
+	})
+
+	var record = func(progress bosh.UploadProgress) {
+		reports = append(reports, progress)
+	}
+
+	var stages = func() []string {
+		var result []string
+		for _, report := range reports {
+			if len(result) == 0 || result[len(result)-1] != report.Stage {
+				result = append(result, report.Stage)
+			}
+		}
+		return result
+	}
+
+	It("reports bytes sent and then the director's progress on the task", func() {
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		reader := strings.NewReader(contents)
+		taskID, err := client.UploadReleaseWithOptions(NewSizeReader(reader, reader.Size()), bosh.UploadOptions{Progress: record})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(taskID).To(Equal(2))
+
+		Expect(stages()).To(Equal([]string{bosh.UploadStageSending, bosh.UploadStageProcessing}))
+
+		var sent []bosh.UploadProgress
+		for _, report := range reports {
+			if report.Stage == bosh.UploadStageSending {
+				sent = append(sent, report)
+			}
+		}
+
+		for i, report := range sent {
+			Expect(report.TotalBytes).To(Equal(int64(len(contents))))
+			if i > 0 {
+				Expect(report.BytesSent).To(BeNumerically(">", sent[i-1].BytesSent))
+			}
+		}
+
+		last := sent[len(sent)-1]
+		Expect(last.BytesSent).To(Equal(int64(len(contents))))
+		Expect(last.Percent()).To(Equal(100.0))
+		Expect(last.BytesPerSecond).To(BeNumerically(">", 0))
+
+		processing := reports[len(sent):]
+		Expect(processing).To(HaveLen(2))
+		Expect(processing[0].TaskID).To(Equal(2))
+		Expect(processing[0].Event.Stage).To(Equal("Extracting release"))
+		Expect(processing[0].Event.State).To(Equal("started"))
+		Expect(processing[1].Event.State).To(Equal("finished"))
+	})
+
+	It("reports progress for stemcell uploads", func() {
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		reader := strings.NewReader(contents)
+		_, err := client.UploadStemcellWithOptions(NewSizeReader(reader, reader.Size()), bosh.UploadOptions{Progress: record})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stages()).To(Equal([]string{bosh.UploadStageSending, bosh.UploadStageProcessing}))
+	})
+
+	It("limits how often bytes sent are reported", func() {
+		bosh.SetUploadProgressInterval(time.Hour)
+
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		reader := strings.NewReader(contents)
+		_, err := client.UploadReleaseWithOptions(NewSizeReader(reader, reader.Size()), bosh.UploadOptions{Progress: record})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(reports[0].Stage).To(Equal(bosh.UploadStageSending))
+		Expect(reports[0].BytesSent).To(Equal(int64(len(contents))))
+		Expect(reports[1].Stage).To(Equal(bosh.UploadStageProcessing))
+	})
+
+	It("only reports the director's progress for uploads by url", func() {
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		_, err := client.UploadReleaseURL("https://example.com/release.tgz", "", bosh.UploadOptions{Progress: record})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stages()).To(Equal([]string{bosh.UploadStageProcessing}))
+	})
+
+	It("returns the task failure", func() {
+		taskState = "cancelled"
+
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		reader := strings.NewReader(contents)
+		taskID, err := client.UploadReleaseWithOptions(NewSizeReader(reader, reader.Size()), bosh.UploadOptions{Progress: record})
+		Expect(err).To(MatchError("bosh task was cancelled"))
+		Expect(taskID).To(Equal(2))
+	})
+
+	It("returns the task id along with the error when the task fails", func() {
+		taskState = "error"
+
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		reader := strings.NewReader(contents)
+		taskID, err := client.UploadStemcellWithOptions(NewSizeReader(reader, reader.Size()), bosh.UploadOptions{Progress: record})
+		Expect(err).To(HaveOccurred())
+		Expect(taskID).To(Equal(2))
+	})
+
+	Describe("ProgressWriter", func() {
+		It("writes a line for each report", func() {
+			output := bytes.NewBuffer([]byte{})
+			progress := bosh.ProgressWriter(output)
+
+			progress(bosh.UploadProgress{
+				Stage:          bosh.UploadStageSending,
+				BytesSent:      512 * 1024 * 1024,
+				TotalBytes:     1024 * 1024 * 1024,
+				BytesPerSecond: 2.5 * 1024 * 1024,
+			})
+			progress(bosh.UploadProgress{
+				Stage:  bosh.UploadStageProcessing,
+				TaskID: 2,
+				Event: bosh.TaskOutput{
+					Stage: "Compiling packages",
+					Task:  "golang/abc",
+					Tags:  []string{"api"},
+					State: "started",
+				},
+			})
+
+			Expect(output.String()).To(Equal("uploading: 512.0 MiB / 1.0 GiB (50%, 2.5 MiB/s)\n" +
+				"task 2: Compiling packages > golang/abc [api] (started)\n"))
+		})
+	})
+})
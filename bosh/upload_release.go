@@ -1,9 +1,11 @@
 package bosh
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 )
 
 type SizeReader interface {
@@ -27,14 +29,60 @@ func (r sizeReader) Size() int64 {
 	return r.size
 }
 
+// UploadOptions sets the fix and rebase flags the director accepts for both
+// release and stemcell uploads, and an optional progress callback.
+type UploadOptions struct {
+	Fix      bool
+	Rebase   bool
+	Progress func(UploadProgress)
+}
+
+func (o UploadOptions) query() url.Values {
+	query := url.Values{}
+	if o.Fix {
+		query.Set("fix", "true")
+	}
+
+	if o.Rebase {
+		query.Set("rebase", "true")
+	}
+
+	return query
+}
+
 func (c Client) UploadRelease(contents SizeReader) (int, error) {
-	request, err := http.NewRequest("POST", fmt.Sprintf("%s/releases", c.config.URL), contents)
+	return c.UploadReleaseContext(context.Background(), contents)
+}
+
+func (c Client) UploadReleaseContext(ctx context.Context, contents SizeReader) (int, error) {
+	return c.UploadReleaseWithOptionsContext(ctx, contents, UploadOptions{})
+}
+
+func (c Client) UploadReleaseWithOptions(contents SizeReader, options UploadOptions) (int, error) {
+	return c.UploadReleaseWithOptionsContext(context.Background(), contents, options)
+}
+
+func (c Client) UploadReleaseWithOptionsContext(ctx context.Context, contents SizeReader, options UploadOptions) (int, error) {
+	return c.upload(ctx, "/releases", options.query(), "application/x-compressed", contents, contents.Size(), options.Progress)
+}
+
+func (c Client) upload(ctx context.Context, path string, query url.Values, contentType string, contents io.Reader, size int64, progress func(UploadProgress)) (int, error) {
+	uploadURL := fmt.Sprintf("%s%s", c.config.URL, path)
+	if len(query) > 0 {
+		uploadURL = fmt.Sprintf("%s?%s", uploadURL, query.Encode())
+	}
+
+	if progress != nil {
+		contents = newProgressReader(contents, size, progress)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "POST", uploadURL, contents)
 	if err != nil {
 		return 0, err
 	}
 
-	request.Header.Set("Content-Type", "application/x-compressed")
-	request.ContentLength = contents.Size()
+	request.Header.Set("Content-Type", contentType)
+	request.ContentLength = size
 
 	response, err := c.makeRequest(request)
 	if err != nil {
@@ -48,8 +96,12 @@ func (c Client) UploadRelease(contents SizeReader) (int, error) {
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusFound {
-		return 0, fmt.Errorf("unexpected response %s:\n%s", response.Status, body)
+		return 0, newDirectorError(response, body)
+	}
+
+	if progress != nil {
+		return c.watchUploadTask(ctx, response.Header.Get("Location"), progress)
 	}
 
-	return c.checkTaskStatus(response.Header.Get("Location"))
+	return c.checkTaskStatus(ctx, response.Header.Get("Location"))
 }
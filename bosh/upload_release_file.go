@@ -0,0 +1,127 @@
+package bosh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh/tarball"
+)
+
+type ReleaseUploadResult struct {
+	Name            string
+	Version         string
+	Skipped         bool
+	TaskID          int
+	MatchedPackages []string
+}
+
+func (c Client) UploadReleaseFile(releasePath string, options UploadOptions) (ReleaseUploadResult, error) {
+	return c.UploadReleaseFileContext(context.Background(), releasePath, options)
+}
+
+func (c Client) UploadReleaseFileContext(ctx context.Context, releasePath string, options UploadOptions) (ReleaseUploadResult, error) {
+	manifest, err := tarball.ReadRelease(releasePath)
+	if err != nil {
+		return ReleaseUploadResult{}, err
+	}
+
+	result := ReleaseUploadResult{
+		Name:    manifest.Name,
+		Version: manifest.Version,
+	}
+
+	if !options.Fix && !options.Rebase {
+		release, err := c.ReleaseContext(ctx, manifest.Name)
+		if err != nil && !errors.Is(err, ErrReleaseNotFound) {
+			return ReleaseUploadResult{}, err
+		}
+
+		for _, version := range release.Versions {
+			if version == manifest.Version {
+				result.Skipped = true
+				return result, nil
+			}
+		}
+
+		result.MatchedPackages, err = c.matchPackages(ctx, manifest.Manifest)
+		if err != nil {
+			return ReleaseUploadResult{}, err
+		}
+	}
+
+	uploadPath := releasePath
+	if len(result.MatchedPackages) > 0 {
+		matched := map[string]bool{}
+		for _, fingerprint := range result.MatchedPackages {
+			matched[fingerprint] = true
+		}
+
+		var skip []string
+		for _, pkg := range manifest.Packages {
+			if matched[pkg.Fingerprint] {
+				skip = append(skip, fmt.Sprintf("packages/%s.tgz", pkg.Name))
+			}
+		}
+
+		uploadPath, err = tarball.Repack(releasePath, skip)
+		if err != nil {
+			return ReleaseUploadResult{}, err
+		}
+		defer os.Remove(uploadPath)
+	}
+
+	file, err := os.Open(uploadPath)
+	if err != nil {
+		return ReleaseUploadResult{}, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return ReleaseUploadResult{}, err
+	}
+
+	result.TaskID, err = c.UploadReleaseWithOptionsContext(ctx, NewSizeReader(file, info.Size()), options)
+	if err != nil {
+		return ReleaseUploadResult{}, err
+	}
+
+	return result, nil
+}
+
+func (c Client) matchPackages(ctx context.Context, rawManifest []byte) ([]string, error) {
+	request, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/packages/matches", c.config.URL), bytes.NewReader(rawManifest))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "text/yaml")
+
+	response, err := c.makeRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := bodyReader(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, newDirectorError(response, body)
+	}
+
+	var fingerprints []string
+	err = json.Unmarshal(body, &fingerprints)
+	if err != nil {
+		return nil, err
+	}
+
+	return fingerprints, nil
+}
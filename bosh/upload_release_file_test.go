@@ -0,0 +1,437 @@
+package bosh_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UploadReleaseFile", func() {
+	var (
+		dir             string
+		releasePath     string
+		releaseManifest string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "release")
+		Expect(err).NotTo(HaveOccurred())
+
+		releaseManifest = `---
+name: some-release
+version: "2"
+commit_hash: abc123
+uncommitted_changes: false
+jobs:
+- name: some-job
+  version: job-fingerprint
+  fingerprint: job-fingerprint
+  sha1: job-sha1
+packages:
+- name: golang
+  version: golang-fingerprint
+  fingerprint: golang-fingerprint
+  sha1: golang-sha1
+  dependencies: []
+- name: server
+  version: server-fingerprint
+  fingerprint: server-fingerprint
+  sha1: server-sha1
+  dependencies: [golang]
+`
+
+		releasePath = filepath.Join(dir, "release.tgz")
+		Expect(ioutil.WriteFile(releasePath, buildTarball(map[string]string{
+			"./release.MF":          releaseManifest,
+			"./jobs/some-job.tgz":   string(buildTarball(map[string]string{"./job.MF": "name: some-job\n"})),
+			"./packages/golang.tgz": "golang",
+			"./packages/server.tgz": "server",
+		}), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("uploads the whole release when the director has none of it", func() {
+		var calls []string
+		var uploadedEntries []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(username).To(Equal("some-username"))
+			Expect(password).To(Equal("some-password"))
+
+			calls = append(calls, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+
+			switch fmt.Sprintf("%s %s", r.Method, r.URL.Path) {
+			case "GET /releases/some-release":
+				w.Write([]byte(`{"versions": ["1"]}`))
+			case "POST /packages/matches":
+				Expect(r.Header.Get("Content-Type")).To(Equal("text/yaml"))
+
+				body, err := ioutil.ReadAll(r.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(body)).To(Equal(releaseManifest))
+
+				w.Write([]byte(`[]`))
+			case "POST /releases":
+				Expect(r.Header.Get("Content-Type")).To(Equal("application/x-compressed"))
+				Expect(r.URL.RawQuery).To(BeEmpty())
+				uploadedEntries = tarballEntries(r.Body)
+
+				w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/6", r.Host))
+				w.WriteHeader(http.StatusFound)
+			case "GET /tasks/6":
+				w.Write([]byte(`{"id": 6, "state": "done"}`))
+			default:
+				Fail(fmt.Sprintf("unhandled request to %s %s", r.Method, r.URL.Path))
+			}
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			Username:            "some-username",
+			Password:            "some-password",
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		result, err := client.UploadReleaseFile(releasePath, bosh.UploadOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Name).To(Equal("some-release"))
+		Expect(result.Version).To(Equal("2"))
+		Expect(result.Skipped).To(BeFalse())
+		Expect(result.TaskID).To(Equal(6))
+		Expect(result.MatchedPackages).To(BeEmpty())
+		Expect(calls).To(Equal([]string{
+			"GET /releases/some-release",
+			"POST /packages/matches",
+			"POST /releases",
+			"GET /tasks/6",
+		}))
+		Expect(uploadedEntries).To(Equal([]string{
+			"./jobs/some-job.tgz",
+			"./packages/golang.tgz",
+			"./packages/server.tgz",
+			"./release.MF",
+		}))
+	})
+
+	It("uploads the release when the director has never seen it", func() {
+		var calls []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(username).To(Equal("some-username"))
+			Expect(password).To(Equal("some-password"))
+
+			calls = append(calls, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+
+			switch fmt.Sprintf("%s %s", r.Method, r.URL.Path) {
+			case "GET /releases/some-release":
+				w.WriteHeader(http.StatusNotFound)
+			case "POST /packages/matches":
+				Expect(r.Header.Get("Content-Type")).To(Equal("text/yaml"))
+
+				body, err := ioutil.ReadAll(r.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(body)).To(Equal(releaseManifest))
+
+				w.Write([]byte(`[]`))
+			case "POST /releases":
+				Expect(r.Header.Get("Content-Type")).To(Equal("application/x-compressed"))
+
+				w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/6", r.Host))
+				w.WriteHeader(http.StatusFound)
+			case "GET /tasks/6":
+				w.Write([]byte(`{"id": 6, "state": "done"}`))
+			default:
+				Fail(fmt.Sprintf("unhandled request to %s %s", r.Method, r.URL.Path))
+			}
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			Username:            "some-username",
+			Password:            "some-password",
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		result, err := client.UploadReleaseFile(releasePath, bosh.UploadOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Skipped).To(BeFalse())
+		Expect(result.TaskID).To(Equal(6))
+		Expect(calls).To(ContainElement("POST /releases"))
+	})
+
+	It("skips the upload when the version already exists", func() {
+		var calls []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(username).To(Equal("some-username"))
+			Expect(password).To(Equal("some-password"))
+
+			calls = append(calls, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+
+			switch fmt.Sprintf("%s %s", r.Method, r.URL.Path) {
+			case "GET /releases/some-release":
+				w.Write([]byte(`{"versions": ["1", "2"]}`))
+			default:
+				Fail(fmt.Sprintf("unhandled request to %s %s", r.Method, r.URL.Path))
+			}
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			Username:            "some-username",
+			Password:            "some-password",
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		result, err := client.UploadReleaseFile(releasePath, bosh.UploadOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result).To(Equal(bosh.ReleaseUploadResult{
+			Name:    "some-release",
+			Version: "2",
+			Skipped: true,
+		}))
+		Expect(calls).To(Equal([]string{
+			"GET /releases/some-release",
+		}))
+	})
+
+	It("leaves out the packages the director already has", func() {
+		var calls []string
+		var uploadedEntries []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(username).To(Equal("some-username"))
+			Expect(password).To(Equal("some-password"))
+
+			calls = append(calls, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+
+			switch fmt.Sprintf("%s %s", r.Method, r.URL.Path) {
+			case "GET /releases/some-release":
+				w.Write([]byte(`{"versions": ["1"]}`))
+			case "POST /packages/matches":
+				Expect(r.Header.Get("Content-Type")).To(Equal("text/yaml"))
+
+				body, err := ioutil.ReadAll(r.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(body)).To(Equal(releaseManifest))
+
+				w.Write([]byte(`["golang-fingerprint"]`))
+			case "POST /releases":
+				Expect(r.Header.Get("Content-Type")).To(Equal("application/x-compressed"))
+				uploadedEntries = tarballEntries(r.Body)
+
+				w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/6", r.Host))
+				w.WriteHeader(http.StatusFound)
+			case "GET /tasks/6":
+				w.Write([]byte(`{"id": 6, "state": "done"}`))
+			default:
+				Fail(fmt.Sprintf("unhandled request to %s %s", r.Method, r.URL.Path))
+			}
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			Username:            "some-username",
+			Password:            "some-password",
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		result, err := client.UploadReleaseFile(releasePath, bosh.UploadOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.MatchedPackages).To(Equal([]string{"golang-fingerprint"}))
+		Expect(uploadedEntries).To(Equal([]string{
+			"./jobs/some-job.tgz",
+			"./packages/server.tgz",
+			"./release.MF",
+		}))
+	})
+
+	It("uploads the whole release in fix mode", func() {
+		var calls []string
+		var uploadedEntries []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(username).To(Equal("some-username"))
+			Expect(password).To(Equal("some-password"))
+
+			calls = append(calls, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+
+			switch fmt.Sprintf("%s %s", r.Method, r.URL.Path) {
+			case "POST /releases":
+				Expect(r.Header.Get("Content-Type")).To(Equal("application/x-compressed"))
+				Expect(r.URL.RawQuery).To(Equal("fix=true"))
+				uploadedEntries = tarballEntries(r.Body)
+
+				w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/6", r.Host))
+				w.WriteHeader(http.StatusFound)
+			case "GET /tasks/6":
+				w.Write([]byte(`{"id": 6, "state": "done"}`))
+			default:
+				Fail(fmt.Sprintf("unhandled request to %s %s", r.Method, r.URL.Path))
+			}
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			Username:            "some-username",
+			Password:            "some-password",
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		result, err := client.UploadReleaseFile(releasePath, bosh.UploadOptions{Fix: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Skipped).To(BeFalse())
+
+		Expect(calls).To(Equal([]string{
+			"POST /releases",
+			"GET /tasks/6",
+		}))
+		Expect(uploadedEntries).To(HaveLen(4))
+	})
+
+	It("uploads the whole release in rebase mode", func() {
+		var calls []string
+		var uploadedEntries []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(username).To(Equal("some-username"))
+			Expect(password).To(Equal("some-password"))
+
+			calls = append(calls, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+
+			switch fmt.Sprintf("%s %s", r.Method, r.URL.Path) {
+			case "POST /releases":
+				Expect(r.Header.Get("Content-Type")).To(Equal("application/x-compressed"))
+				Expect(r.URL.RawQuery).To(Equal("rebase=true"))
+				uploadedEntries = tarballEntries(r.Body)
+
+				w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/6", r.Host))
+				w.WriteHeader(http.StatusFound)
+			case "GET /tasks/6":
+				w.Write([]byte(`{"id": 6, "state": "done"}`))
+			default:
+				Fail(fmt.Sprintf("unhandled request to %s %s", r.Method, r.URL.Path))
+			}
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			Username:            "some-username",
+			Password:            "some-password",
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		result, err := client.UploadReleaseFile(releasePath, bosh.UploadOptions{Rebase: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Skipped).To(BeFalse())
+
+		Expect(calls).To(Equal([]string{
+			"POST /releases",
+			"GET /tasks/6",
+		}))
+		Expect(uploadedEntries).To(HaveLen(4))
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when the tarball has no release.MF", func() {
+			client := bosh.NewClient(bosh.Config{
+				URL: "",
+			})
+
+			Expect(ioutil.WriteFile(releasePath, buildTarball(map[string]string{
+				"./packages/golang.tgz": "golang",
+			}), 0644)).To(Succeed())
+
+			_, err := client.UploadReleaseFile(releasePath, bosh.UploadOptions{})
+			Expect(err).To(MatchError(fmt.Sprintf("failed to read release %s: release.MF not found", releasePath)))
+		})
+
+		It("returns an error when the tarball does not exist", func() {
+			client := bosh.NewClient(bosh.Config{
+				URL: "",
+			})
+
+			_, err := client.UploadReleaseFile(filepath.Join(dir, "missing.tgz"), bosh.UploadOptions{})
+			Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+		})
+
+		It("returns an error when release.MF is malformed", func() {
+			client := bosh.NewClient(bosh.Config{
+				URL: "",
+			})
+
+			Expect(ioutil.WriteFile(releasePath, buildTarball(map[string]string{
+				"./release.MF": "%%%",
+			}), 0644)).To(Succeed())
+
+			_, err := client.UploadReleaseFile(releasePath, bosh.UploadOptions{})
+			Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("failed to read release %s: failed to parse release.MF", releasePath))))
+		})
+
+		It("returns an error when the package matches cannot be fetched", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch fmt.Sprintf("%s %s", r.Method, r.URL.Path) {
+				case "GET /releases/some-release":
+					w.Write([]byte(`{"versions": ["1"]}`))
+				case "POST /packages/matches":
+					w.Write([]byte("%%%"))
+				default:
+					Fail(fmt.Sprintf("unhandled request to %s %s", r.Method, r.URL.Path))
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			_, err := client.UploadReleaseFile(releasePath, bosh.UploadOptions{})
+			Expect(err).To(MatchError(ContainSubstring("invalid character")))
+		})
+	})
+})
+
+func tarballEntries(tarball io.Reader) []string {
+	gzipReader, err := gzip.NewReader(tarball)
+	Expect(err).NotTo(HaveOccurred())
+
+	var entries []string
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		Expect(err).NotTo(HaveOccurred())
+
+		entries = append(entries, header.Name)
+	}
+
+	sort.Strings(entries)
+	return entries
+}
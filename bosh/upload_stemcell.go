@@ -1,33 +1,21 @@
 package bosh
 
 import (
-	"fmt"
-	"net/http"
+	"context"
 )
 
 func (c Client) UploadStemcell(contents SizeReader) (int, error) {
-	request, err := http.NewRequest("POST", fmt.Sprintf("%s/stemcells", c.config.URL), contents)
-	if err != nil {
-		return 0, err
-	}
-
-	request.Header.Set("Content-Type", "application/x-compressed")
-	request.ContentLength = contents.Size()
-
-	response, err := c.makeRequest(request)
-	if err != nil {
-		return 0, err
-	}
+	return c.UploadStemcellContext(context.Background(), contents)
+}
 
-	body, err := bodyReader(response.Body)
-	if err != nil {
-		return 0, err
-	}
-	defer response.Body.Close()
+func (c Client) UploadStemcellContext(ctx context.Context, contents SizeReader) (int, error) {
+	return c.UploadStemcellWithOptionsContext(ctx, contents, UploadOptions{})
+}
 
-	if response.StatusCode != http.StatusFound {
-		return 0, fmt.Errorf("unexpected response %s:\n%s", response.Status, body)
-	}
+func (c Client) UploadStemcellWithOptions(contents SizeReader, options UploadOptions) (int, error) {
+	return c.UploadStemcellWithOptionsContext(context.Background(), contents, options)
+}
 
-	return c.checkTaskStatus(response.Header.Get("Location"))
+func (c Client) UploadStemcellWithOptionsContext(ctx context.Context, contents SizeReader, options UploadOptions) (int, error) {
+	return c.upload(ctx, "/stemcells", options.query(), "application/x-compressed", contents, contents.Size(), options.Progress)
 }
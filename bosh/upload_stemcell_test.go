@@ -63,6 +63,31 @@ var _ = Describe("UploadStemcell", func() {
 		Expect(taskID).To(Equal(8))
 	})
 
+	It("sends the fix and rebase flags", func() {
+		var rawQuery string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			switch req.URL.Path {
+			case "/stemcells":
+				rawQuery = req.URL.RawQuery
+
+				w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/8", req.Host))
+				w.WriteHeader(http.StatusFound)
+			case "/tasks/8":
+				w.Write([]byte(`{"id": 8, "state": "done"}`))
+			default:
+				Fail(fmt.Sprintf("unhandled request to %s", req.URL.Path))
+			}
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL: server.URL,
+		})
+
+		_, err := client.UploadStemcellWithOptions(strings.NewReader("I am an apple"), bosh.UploadOptions{Fix: true, Rebase: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rawQuery).To(Equal("fix=true&rebase=true"))
+	})
+
 	Context("failure cases", func() {
 		Context("when the request cannot be created", func() {
 			It("returns an error", func() {
@@ -0,0 +1,109 @@
+package bosh
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var digestLengths = map[string]int{
+	"sha1":   40,
+	"sha256": 64,
+	"sha512": 128,
+}
+
+func (c Client) UploadReleaseURL(location, digest string, options UploadOptions) (int, error) {
+	return c.UploadReleaseURLContext(context.Background(), location, digest, options)
+}
+
+func (c Client) UploadReleaseURLContext(ctx context.Context, location, digest string, options UploadOptions) (int, error) {
+	return c.uploadURL(ctx, "/releases", location, digest, options.query(), options.Progress)
+}
+
+func (c Client) UploadStemcellURL(location, digest string, options UploadOptions) (int, error) {
+	return c.UploadStemcellURLContext(context.Background(), location, digest, options)
+}
+
+func (c Client) UploadStemcellURLContext(ctx context.Context, location, digest string, options UploadOptions) (int, error) {
+	return c.uploadURL(ctx, "/stemcells", location, digest, options.query(), options.Progress)
+}
+
+func (c Client) uploadURL(ctx context.Context, path, location, digest string, query url.Values, progress func(UploadProgress)) (int, error) {
+	if location == "" {
+		return 0, errors.New("a location is required to upload by url")
+	}
+
+	digest, err := normalizeDigest(digest)
+	if err != nil {
+		return 0, err
+	}
+
+	payload, err := json.Marshal(struct {
+		Location string `json:"location"`
+		SHA1     string `json:"sha1,omitempty"`
+	}{
+		Location: location,
+		SHA1:     digest,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Only the director's progress is worth reporting; the request body is
+	// just the location.
+	var taskProgress func(UploadProgress)
+	if progress != nil {
+		taskProgress = func(p UploadProgress) {
+			if p.Stage == UploadStageProcessing {
+				progress(p)
+			}
+		}
+	}
+
+	return c.upload(ctx, path, query, "application/json", bytes.NewReader(payload), int64(len(payload)), taskProgress)
+}
+
+// normalizeDigest accepts a bare sha1 or a multi-digest string such as
+// "sha256:abc;sha1:def", which the director verifies as a whole.
+func normalizeDigest(digest string) (string, error) {
+	digest = strings.TrimSpace(digest)
+	if digest == "" {
+		return "", nil
+	}
+
+	var digests []string
+	for _, part := range strings.Split(digest, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		algorithm, value := "sha1", part
+		if i := strings.Index(part, ":"); i >= 0 {
+			algorithm, value = strings.ToLower(part[:i]), part[i+1:]
+		}
+
+		length, ok := digestLengths[algorithm]
+		if !ok {
+			return "", fmt.Errorf("invalid digest %q: unsupported algorithm %q", digest, algorithm)
+		}
+
+		_, err := hex.DecodeString(value)
+		if err != nil || len(value) != length {
+			return "", fmt.Errorf("invalid digest %q: malformed %s value %q", digest, algorithm, value)
+		}
+
+		if strings.Contains(part, ":") {
+			digests = append(digests, fmt.Sprintf("%s:%s", algorithm, strings.ToLower(value)))
+		} else {
+			digests = append(digests, strings.ToLower(value))
+		}
+	}
+
+	return strings.Join(digests, ";"), nil
+}
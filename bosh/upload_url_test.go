@@ -0,0 +1,229 @@
+package bosh_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("upload by url", func() {
+	var (
+		sha1   = strings.Repeat("a", 40)
+		sha256 = strings.Repeat("b", 64)
+	)
+
+	Describe("UploadReleaseURL", func() {
+		It("asks the director to fetch the release", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				username, password, ok := r.BasicAuth()
+				Expect(ok).To(BeTrue())
+				Expect(username).To(Equal("some-username"))
+				Expect(password).To(Equal("some-password"))
+
+				switch r.URL.Path {
+				case "/releases":
+					Expect(r.Method).To(Equal("POST"))
+					Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+					Expect(r.URL.RawQuery).To(BeEmpty())
+
+					var payload map[string]string
+					Expect(json.NewDecoder(r.Body).Decode(&payload)).To(Succeed())
+					Expect(payload).To(Equal(map[string]string{
+						"location": "https://example.com/release.tgz",
+						"sha1":     sha1,
+					}))
+
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/4", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/4":
+					w.Write([]byte(`{"id": 4, "state": "done"}`))
+				default:
+					Fail(fmt.Sprintf("unhandled request to %s", r.URL.Path))
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:      server.URL,
+				Username: "some-username",
+				Password: "some-password",
+			})
+
+			taskID, err := client.UploadReleaseURL("https://example.com/release.tgz", sha1, bosh.UploadOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(taskID).To(Equal(4))
+		})
+
+		It("sends the fix and rebase flags", func() {
+			var rawQuery string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/releases":
+					rawQuery = r.URL.RawQuery
+
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/4", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/4":
+					w.Write([]byte(`{"id": 4, "state": "done"}`))
+				default:
+					Fail(fmt.Sprintf("unhandled request to %s", r.URL.Path))
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			_, err := client.UploadReleaseURL("https://example.com/release.tgz", sha1, bosh.UploadOptions{
+				Fix:    true,
+				Rebase: true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rawQuery).To(Equal("fix=true&rebase=true"))
+		})
+
+		It("omits the digest when none is given", func() {
+			var payload map[string]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/releases":
+					Expect(json.NewDecoder(r.Body).Decode(&payload)).To(Succeed())
+
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/4", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/4":
+					w.Write([]byte(`{"id": 4, "state": "done"}`))
+				default:
+					Fail(fmt.Sprintf("unhandled request to %s", r.URL.Path))
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			_, err := client.UploadReleaseURL("https://example.com/release.tgz", "", bosh.UploadOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(payload).To(Equal(map[string]string{
+				"location": "https://example.com/release.tgz",
+			}))
+		})
+	})
+
+	Describe("UploadStemcellURL", func() {
+		It("asks the director to fetch the stemcell with a multi-digest", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/stemcells":
+					Expect(r.Method).To(Equal("POST"))
+					Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+					Expect(r.URL.RawQuery).To(Equal("fix=true&rebase=true"))
+
+					var payload map[string]string
+					Expect(json.NewDecoder(r.Body).Decode(&payload)).To(Succeed())
+					Expect(payload).To(Equal(map[string]string{
+						"location": "https://example.com/stemcell.tgz",
+						"sha1":     fmt.Sprintf("sha256:%s;sha1:%s", sha256, sha1),
+					}))
+
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/4", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/4":
+					w.Write([]byte(`{"id": 4, "state": "done"}`))
+				default:
+					Fail(fmt.Sprintf("unhandled request to %s", r.URL.Path))
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			digest := fmt.Sprintf(" SHA256:%s ; sha1:%s ", strings.ToUpper(sha256), sha1)
+
+			taskID, err := client.UploadStemcellURL("https://example.com/stemcell.tgz", digest, bosh.UploadOptions{
+				Fix:    true,
+				Rebase: true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(taskID).To(Equal(4))
+		})
+	})
+
+	Describe("UploadReleaseWithOptions", func() {
+		It("sends the fix and rebase flags with the tarball", func() {
+			var rawQuery string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/releases":
+					Expect(r.Header.Get("Content-Type")).To(Equal("application/x-compressed"))
+					rawQuery = r.URL.RawQuery
+
+					w.Header().Set("Location", fmt.Sprintf("http://%s/tasks/4", r.Host))
+					w.WriteHeader(http.StatusFound)
+				case "/tasks/4":
+					w.Write([]byte(`{"id": 4, "state": "done"}`))
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			reader := strings.NewReader("I am a banana!")
+			_, err := client.UploadReleaseWithOptions(NewSizeReader(reader, reader.Size()), bosh.UploadOptions{Fix: true, Rebase: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rawQuery).To(Equal("fix=true&rebase=true"))
+		})
+	})
+
+	Context("failure cases", func() {
+		DescribeTable("rejects malformed digests before contacting the director",
+			func(digest, message string) {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					Fail(fmt.Sprintf("unexpected request to %s", r.URL.Path))
+				}))
+
+				client := bosh.NewClient(bosh.Config{
+					URL: server.URL,
+				})
+
+				_, err := client.UploadReleaseURL("https://example.com/release.tgz", digest, bosh.UploadOptions{})
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("unknown algorithm", "md5:abc", `unsupported algorithm "md5"`),
+			Entry("short sha1", "abc", `malformed sha1 value "abc"`),
+			Entry("non-hex sha256", "sha256:"+strings.Repeat("z", 64), "malformed sha256 value"),
+		)
+
+		It("requires a location", func() {
+			client := bosh.NewClient(bosh.Config{
+				URL: "",
+			})
+
+			_, err := client.UploadStemcellURL("", sha1, bosh.UploadOptions{})
+			Expect(err).To(MatchError("a location is required to upload by url"))
+		})
+
+		It("returns an error on an unexpected status code", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte("More Info"))
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL: server.URL,
+			})
+
+			_, err := client.UploadStemcellURL("https://example.com/stemcell.tgz", sha1, bosh.UploadOptions{})
+			Expect(err).To(MatchError("unexpected response 400 Bad Request:\nMore Info"))
+		})
+	})
+})
@@ -2,12 +2,17 @@ package bosh
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 )
 
 func (c Client) SetVMResurrection(deploymentName, jobName string, jobIndex int, enable bool) error {
-	request, err := http.NewRequest("PUT", fmt.Sprintf("%s/deployments/%s/jobs/%s/%d/resurrection", c.config.URL, deploymentName, jobName, jobIndex), bytes.NewBuffer([]byte(fmt.Sprintf(`{"resurrection_paused": %t}`, !enable))))
+	return c.SetVMResurrectionContext(context.Background(), deploymentName, jobName, jobIndex, enable)
+}
+
+func (c Client) SetVMResurrectionContext(ctx context.Context, deploymentName, jobName string, jobIndex int, enable bool) error {
+	request, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/deployments/%s/jobs/%s/%d/resurrection", c.config.URL, deploymentName, jobName, jobIndex), bytes.NewBuffer([]byte(fmt.Sprintf(`{"resurrection_paused": %t}`, !enable))))
 	if err != nil {
 		return err
 	}
@@ -26,7 +31,9 @@ func (c Client) SetVMResurrection(deploymentName, jobName string, jobIndex int,
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected response %d %s: %s", resp.StatusCode, http.StatusText(resp.StatusCode), body)
+		directorErr := newDirectorError(resp, body)
+		directorErr.message = fmt.Sprintf("unexpected response %d %s: %s", resp.StatusCode, http.StatusText(resp.StatusCode), body)
+		return directorErr
 	}
 
 	return nil
@@ -0,0 +1,121 @@
+package bosh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func (c Client) WatchTask(taskId int, handler func(TaskOutput)) error {
+	return c.WatchTaskContext(context.Background(), taskId, handler)
+}
+
+func (c Client) WatchTaskContext(ctx context.Context, taskId int, handler func(TaskOutput)) error {
+	return c.watchTask(ctx, taskId, handler, false)
+}
+
+// Watchers of someone else's task must never cancel it, so cancelOnDone is
+// only set by operations like Deploy that created the task themselves.
+func (c Client) watchTask(ctx context.Context, taskId int, handler func(TaskOutput), cancelOnDone bool) error {
+	location := fmt.Sprintf("%s/tasks/%d", c.config.URL, taskId)
+	stopWatching := func() error {
+		if !cancelOnDone {
+			return ctx.Err()
+		}
+
+		_, err := c.abandonTask(location, ctx.Err())
+		return err
+	}
+
+	var offset int64
+	for {
+		task, err := c.checkTask(ctx, location)
+		if err != nil {
+			if ctx.Err() != nil {
+				return stopWatching()
+			}
+			return err
+		}
+
+		terminal := task.State == "done" || task.State == "error" || task.State == "errored" || task.State == "cancelled"
+
+		chunk, err := c.taskOutputFrom(ctx, taskId, "event", offset)
+		if err != nil {
+			if ctx.Err() != nil {
+				return stopWatching()
+			}
+			return err
+		}
+
+		lines := chunk
+		if !terminal {
+			lines = chunk[:bytes.LastIndexByte(chunk, '\n')+1]
+		}
+		offset += int64(len(lines))
+
+		for _, line := range bytes.Split(lines, []byte("\n")) {
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 {
+				continue
+			}
+
+			var taskOutput TaskOutput
+			err = json.Unmarshal(line, &taskOutput)
+			if err != nil {
+				return err
+			}
+
+			handler(taskOutput)
+		}
+
+		if terminal {
+			_, err := c.finishedTask(ctx, task)
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return stopWatching()
+		case <-time.After(c.config.TaskPollingInterval):
+		}
+	}
+}
+
+func (c Client) taskOutputFrom(ctx context.Context, taskId int, outputType string, offset int64) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/tasks/%d/output?type=%s", c.config.URL, taskId, outputType), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset > 0 {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	response, err := c.makeRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := bodyReader(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusRequestedRangeNotSatisfiable, http.StatusNoContent:
+		return nil, nil
+	case http.StatusPartialContent:
+		return body, nil
+	case http.StatusOK:
+		if int64(len(body)) < offset {
+			return nil, nil
+		}
+		return body[offset:], nil
+	default:
+		return nil, newDirectorError(response, body)
+	}
+}
@@ -0,0 +1,191 @@
+package bosh_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/pivotal-cf-experimental/bosh-test/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WatchTask", func() {
+	var (
+		events    []string
+		callCount int
+		ranges    []string
+		server    *httptest.Server
+	)
+
+	BeforeEach(func() {
+		events = []string{
+			`{"time": 1, "stage": "Preparing deployment", "tags": [], "total": 1, "task": "Binding deployment", "index": 1, "state": "started", "progress": 0}` + "\n",
+			`{"time": 2, "stage": "Preparing deployment", "tags": [], "total": 1, "task": "Binding deployment", "index": 1, "state": "finished", "progress": 100}` + "\n" +
+				`{"time": 3, "stage": "Updating instance", "tags": ["some-job"], "total": 1, "task": "some-job/0", "index": 1, "st`,
+			`ate": "started", "progress": 0}` + "\n",
+		}
+		callCount = 0
+		ranges = []string{}
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/tasks/1":
+				Expect(r.Method).To(Equal("GET"))
+
+				callCount++
+				if callCount == len(events) {
+					w.Write([]byte(`{"id": 1, "state": "done"}`))
+				} else {
+					w.Write([]byte(`{"id": 1, "state": "processing"}`))
+				}
+			case "/tasks/1/output":
+				Expect(r.URL.RawQuery).To(Equal("type=event"))
+				ranges = append(ranges, r.Header.Get("Range"))
+
+				var output bytes.Buffer
+				for _, event := range events[:callCount] {
+					output.WriteString(event)
+				}
+
+				http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(output.Bytes()))
+			default:
+				Fail("could not match any URL endpoints")
+			}
+		}))
+	})
+
+	It("calls the handler with each new event until the task finishes", func() {
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		var outputs []bosh.TaskOutput
+		err := client.WatchTask(1, func(output bosh.TaskOutput) {
+			outputs = append(outputs, output)
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(outputs).To(Equal([]bosh.TaskOutput{
+			{Time: 1, Stage: "Preparing deployment", Tags: []string{}, Total: 1, Task: "Binding deployment", Index: 1, State: "started"},
+			{Time: 2, Stage: "Preparing deployment", Tags: []string{}, Total: 1, Task: "Binding deployment", Index: 1, State: "finished", Progress: 100},
+			{Time: 3, Stage: "Updating instance", Tags: []string{"some-job"}, Total: 1, Task: "some-job/0", Index: 1, State: "started"},
+		}))
+		firstOffset := len(events[0])
+		secondOffset := firstOffset + strings.Index(events[1], "\n") + 1
+		Expect(ranges).To(Equal([]string{
+			"",
+			fmt.Sprintf("bytes=%d-", firstOffset),
+			fmt.Sprintf("bytes=%d-", secondOffset),
+		}))
+	})
+
+	It("returns the task error when the task fails", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/tasks/1":
+				w.Write([]byte(`{"id": 1, "state": "error", "result": "some-error-message"}`))
+			case "/tasks/1/output":
+				w.Write([]byte(`{"error": {"code": 100, "message": "some-better-error-message"}}` + "\n"))
+			default:
+				Fail("could not match any URL endpoints")
+			}
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			TaskPollingInterval: time.Nanosecond,
+		})
+
+		var outputs []bosh.TaskOutput
+		err := client.WatchTask(1, func(output bosh.TaskOutput) {
+			outputs = append(outputs, output)
+		})
+		Expect(err).To(MatchError("bosh task 1 failed with an error status \"some-error-message\":\n  - task error: 100 has occurred: some-better-error-message"))
+		Expect(outputs).To(HaveLen(1))
+	})
+
+	It("stops watching without cancelling the task when the context is done", func() {
+		var cancelled bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/tasks/1":
+				if r.Method == "DELETE" {
+					cancelled = true
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				w.Write([]byte(`{"id": 1, "state": "processing"}`))
+			case "/tasks/1/output":
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			default:
+				Fail("could not match any URL endpoints")
+			}
+		}))
+
+		client := bosh.NewClient(bosh.Config{
+			URL:                 server.URL,
+			TaskPollingInterval: time.Millisecond,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := client.WatchTaskContext(ctx, 1, func(bosh.TaskOutput) {})
+		Expect(err).To(MatchError("context deadline exceeded"))
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		Expect(cancelled).To(BeFalse())
+	})
+
+	Context("failure cases", func() {
+		It("errors on an unexpected output status code with a body", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/tasks/1":
+					w.Write([]byte(`{"id": 1, "state": "processing"}`))
+				case "/tasks/1/output":
+					w.WriteHeader(http.StatusBadGateway)
+					w.Write([]byte("More Info"))
+				default:
+					Fail("could not match any URL endpoints")
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			err := client.WatchTask(1, func(bosh.TaskOutput) {})
+			Expect(err).To(MatchError("unexpected response 502 Bad Gateway:\nMore Info"))
+		})
+
+		It("errors on malformed event JSON", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/tasks/1":
+					w.Write([]byte(`{"id": 1, "state": "processing"}`))
+				case "/tasks/1/output":
+					w.Write([]byte("%%%%%%%%\n"))
+				default:
+					Fail("could not match any URL endpoints")
+				}
+			}))
+
+			client := bosh.NewClient(bosh.Config{
+				URL:                 server.URL,
+				TaskPollingInterval: time.Nanosecond,
+			})
+
+			err := client.WatchTask(1, func(bosh.TaskOutput) {})
+			Expect(err).To(MatchError(ContainSubstring("invalid character")))
+		})
+	})
+})